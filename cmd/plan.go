@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/lovelaze/nebula-sync/internal/service"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show what a sync would change without applying it",
+	Run: func(cmd *cobra.Command, args []string) {
+		readEnvFile()
+
+		service, err := service.Init()
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to initialize service")
+		}
+
+		if err = service.Plan(cmd.Context()); err != nil {
+			log.Fatal().Err(err).Msg("Plan failed")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+	planCmd.Flags().StringVar(&envFile, "env-file", "", "Read env from `.env` file")
+}
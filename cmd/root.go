@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/spf13/cobra"
 
 	"github.com/lovelaze/nebula-sync/internal/log"
@@ -12,8 +17,12 @@ var rootCmd = &cobra.Command{
 	Version: version.Version,
 }
 
+// Execute runs the command with a context that is cancelled on SIGINT or SIGTERM.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return rootCmd.ExecuteContext(ctx)
 }
 
 func init() {
@@ -1,6 +1,7 @@
 package e2e
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -31,7 +32,7 @@ func (suite *testSuite) Test_FullSync() {
 
 	s, err := service.Init()
 	suite.Require().NoError(err)
-	err = s.Run()
+	err = s.Run(context.Background())
 	suite.Require().NoError(err)
 }
 
@@ -43,7 +44,7 @@ func (suite *testSuite) Test_FullSync_SSL() {
 
 	s, err := service.Init()
 	suite.Require().NoError(err)
-	err = s.Run()
+	err = s.Run(context.Background())
 	suite.Require().NoError(err)
 }
 
@@ -57,7 +58,7 @@ func (suite *testSuite) Test_SelectiveSync() {
 
 	s, err := service.Init()
 	suite.Require().NoError(err)
-	err = s.Run()
+	err = s.Run(context.Background())
 	suite.Require().NoError(err)
 }
 
@@ -78,7 +79,7 @@ func (suite *testSuite) Test_SelectiveSync_Include() {
 
 	s, err := service.Init()
 	suite.Require().NoError(err)
-	err = s.Run()
+	err = s.Run(context.Background())
 	suite.Require().NoError(err)
 }
 
@@ -99,7 +100,7 @@ func (suite *testSuite) Test_SelectiveSync_Exclude() {
 
 	s, err := service.Init()
 	suite.Require().NoError(err)
-	err = s.Run()
+	err = s.Run(context.Background())
 	suite.Require().NoError(err)
 }
 
@@ -1,17 +1,148 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/lovelaze/nebula-sync/internal/sync"
 )
 
 func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
-	if s.healthy() {
+	for _, state := range s.states {
+		if !healthy(state) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// statusHandler writes the outcomes of the only sync group, or the outcomes of every group keyed by name.
+func (s *Server) statusHandler(w http.ResponseWriter, r *http.Request) {
+	if len(s.states) == 1 {
+		for _, state := range s.states {
+			writeJSON(w, http.StatusOK, state.Outcomes())
+		}
+		return
+	}
+
+	outcomes := make(map[string][]sync.Outcome, len(s.states))
+	for name, state := range s.states {
+		outcomes[name] = state.Outcomes()
+	}
+	writeJSON(w, http.StatusOK, outcomes)
+}
+
+func (s *Server) groupHealthHandler(w http.ResponseWriter, r *http.Request) {
+	state, exists := s.states[chi.URLParam(r, "group")]
+	switch {
+	case !exists:
+		w.WriteHeader(http.StatusNotFound)
+	case healthy(state):
 		w.WriteHeader(http.StatusOK)
-	} else {
+	default:
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 }
 
-func (s *Server) healthy() bool {
-	return len(s.state.Stack) > 0 && s.state.Stack[0].Success
+func (s *Server) groupStatusHandler(w http.ResponseWriter, r *http.Request) {
+	state, exists := s.states[chi.URLParam(r, "group")]
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, state.Outcomes())
+}
+
+// driftHandler writes the latest drift of the only sync group, or the latest drift of every group keyed by name.
+func (s *Server) driftHandler(w http.ResponseWriter, r *http.Request) {
+	if len(s.states) == 1 {
+		for _, state := range s.states {
+			writeDrift(w, state)
+		}
+		return
+	}
+
+	drifts := make(map[string]*sync.Drift, len(s.states))
+	for name, state := range s.states {
+		drifts[name] = state.Drift()
+	}
+	writeJSON(w, http.StatusOK, drifts)
+}
+
+func (s *Server) groupDriftHandler(w http.ResponseWriter, r *http.Request) {
+	state, exists := s.states[chi.URLParam(r, "group")]
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	writeDrift(w, state)
+}
+
+// writeDrift writes the latest drift of state, or no content if no detect run completed yet.
+func writeDrift(w http.ResponseWriter, state *sync.State) {
+	drift := state.Drift()
+	if drift == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, drift)
+}
+
+// versionsHandler writes the latest versions of the only sync group, or the latest versions of every group keyed by name.
+func (s *Server) versionsHandler(w http.ResponseWriter, r *http.Request) {
+	if len(s.states) == 1 {
+		for _, state := range s.states {
+			writeVersions(w, state)
+		}
+		return
+	}
+
+	versions := make(map[string]*sync.Versions, len(s.states))
+	for name, state := range s.states {
+		versions[name] = state.Versions()
+	}
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func (s *Server) groupVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	state, exists := s.states[chi.URLParam(r, "group")]
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	writeVersions(w, state)
+}
+
+// writeVersions writes the latest versions of state, or no content if no run detected them yet.
+func writeVersions(w http.ResponseWriter, state *sync.State) {
+	versions := state.Versions()
+	if versions == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func healthy(state *sync.State) bool {
+	outcomes := state.Outcomes()
+	return len(outcomes) > 0 && outcomes[0].Success
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Warn().Err(err).Msg("Failed to write response")
+	}
 }
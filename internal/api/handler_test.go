@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -19,7 +20,7 @@ func TestHealthHandler_healthy(t *testing.T) {
 	require.Len(t, state.Stack, 1)
 	require.True(t, state.Stack[0].Success)
 
-	server := NewServer(state)
+	server := NewServer(map[string]*sync.State{"default": state}, nil, "")
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	resp := httptest.NewRecorder()
@@ -39,7 +40,7 @@ func TestHealthHandler_unhealthy(t *testing.T) {
 	require.Len(t, state.Stack, 1)
 	require.False(t, state.Stack[0].Success)
 
-	server := NewServer(state)
+	server := NewServer(map[string]*sync.State{"default": state}, nil, "")
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	resp := httptest.NewRecorder()
@@ -51,3 +52,132 @@ func TestHealthHandler_unhealthy(t *testing.T) {
 
 	assert.Equal(t, 500, result.StatusCode)
 }
+
+func TestStatusHandler(t *testing.T) {
+	state := sync.NewState()
+	state.OnFailure(&sync.ReplicasError{Outcomes: []sync.ReplicaOutcome{
+		{Replica: "http://replica1", Success: true},
+		{Replica: "http://replica2", Success: false, Error: "test error"},
+	}})
+
+	server := NewServer(map[string]*sync.State{"default": state}, nil, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	resp := httptest.NewRecorder()
+
+	server.router.ServeHTTP(resp, req)
+
+	result := resp.Result()
+	defer result.Body.Close()
+
+	var outcomes []sync.Outcome
+	require.NoError(t, json.NewDecoder(result.Body).Decode(&outcomes))
+
+	assert.Equal(t, 200, result.StatusCode)
+	require.Len(t, outcomes, 1)
+	assert.True(t, outcomes[0].Partial)
+	assert.Equal(t, state.Outcomes()[0].Replicas, outcomes[0].Replicas)
+}
+
+func TestStatusHandler_groups(t *testing.T) {
+	home := sync.NewState()
+	home.OnSuccess()
+	office := sync.NewState()
+	office.OnFailure(errors.New("test error"))
+
+	server := NewServer(map[string]*sync.State{"home": home, "office": office}, nil, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	resp := httptest.NewRecorder()
+
+	server.router.ServeHTTP(resp, req)
+
+	result := resp.Result()
+	defer result.Body.Close()
+
+	var outcomes map[string][]sync.Outcome
+	require.NoError(t, json.NewDecoder(result.Body).Decode(&outcomes))
+
+	assert.Equal(t, 200, result.StatusCode)
+	require.Len(t, outcomes["home"], 1)
+	assert.True(t, outcomes["home"][0].Success)
+	require.Len(t, outcomes["office"], 1)
+	assert.False(t, outcomes["office"][0].Success)
+}
+
+func TestGroupHandlers(t *testing.T) {
+	home := sync.NewState()
+	home.OnSuccess()
+	office := sync.NewState()
+	office.OnFailure(errors.New("test error"))
+
+	server := NewServer(map[string]*sync.State{"home": home, "office": office}, nil, "")
+
+	tests := map[string]int{
+		"/health":               500,
+		"/groups/home/health":   200,
+		"/groups/office/health": 500,
+		"/groups/other/health":  404,
+		"/groups/home/status":   200,
+		"/groups/other/status":  404,
+	}
+
+	for path, status := range tests {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		resp := httptest.NewRecorder()
+
+		server.router.ServeHTTP(resp, req)
+
+		assert.Equal(t, status, resp.Code, path)
+	}
+}
+
+func TestDriftHandler(t *testing.T) {
+	state := sync.NewState()
+	server := NewServer(map[string]*sync.State{"default": state}, nil, "")
+
+	resp := httptest.NewRecorder()
+	server.router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/drift", nil))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	state.OnDrift(&sync.Drift{Replicas: []sync.ReplicaDrift{{Replica: "http://replica", Gravity: []string{"adlist"}}}})
+
+	resp = httptest.NewRecorder()
+	server.router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/groups/default/drift", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var drift sync.Drift
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&drift))
+	assert.True(t, drift.Detected())
+
+	resp = httptest.NewRecorder()
+	server.router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/groups/unknown/drift", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestVersionsHandler(t *testing.T) {
+	home, office := sync.NewState(), sync.NewState()
+	server := NewServer(map[string]*sync.State{"home": home, "office": office}, nil, "")
+
+	resp := httptest.NewRecorder()
+	server.router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/groups/home/versions", nil))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	home.OnVersions(&sync.Versions{
+		Primary:  sync.TargetVersion{Target: "http://primary", FTL: "v6.1.0"},
+		Replicas: []sync.TargetVersion{{Target: "http://replica", FTL: "v6.0.4", Compatibility: sync.CompatibilityLimited}},
+	})
+
+	resp = httptest.NewRecorder()
+	server.router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/versions", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var versions map[string]*sync.Versions
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&versions))
+	assert.Nil(t, versions["office"])
+	assert.Equal(t, sync.CompatibilityLimited, versions["home"].Replicas[0].Compatibility)
+
+	resp = httptest.NewRecorder()
+	server.router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/groups/unknown/versions", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
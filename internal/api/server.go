@@ -17,28 +17,49 @@ const (
 )
 
 type Server struct {
-	state  *sync.State
+	states map[string]*sync.State
+	syncer Syncer
+	// token is the bearer token on demand syncs require
+	token  string
 	router *chi.Mux
 }
 
-func NewServer(state *sync.State) *Server {
+// NewServer serves the state of every sync group, keyed by group name. On demand syncs change every replica, they are
+// only served with a syncer and a token.
+func NewServer(states map[string]*sync.State, syncer Syncer, token string) *Server {
 	router := chi.NewRouter()
 	server := &Server{
-		state:  state,
+		states: states,
+		syncer: syncer,
+		token:  token,
 		router: router,
 	}
 
 	router.Get("/health", server.healthHandler)
+	router.Get("/status", server.statusHandler)
+	router.Get("/groups/{group}/health", server.groupHealthHandler)
+	router.Get("/groups/{group}/status", server.groupStatusHandler)
+	router.Get("/drift", server.driftHandler)
+	router.Get("/groups/{group}/drift", server.groupDriftHandler)
+	router.Get("/versions", server.versionsHandler)
+	router.Get("/groups/{group}/versions", server.groupVersionsHandler)
+	if syncer != nil && token != "" {
+		router.With(server.authorize).Post("/sync", server.syncHandler)
+	}
 
 	return server
 }
 
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
 func (s *Server) Start() {
 	go func() {
 		log.Debug().Msg("Starting http server")
 
 		server := &http.Server{
-			Handler:           s.router,
+			Handler:           s,
 			Addr:              fmt.Sprintf(":%d", port),
 			ReadHeaderTimeout: readHeaderTimeout,
 		}
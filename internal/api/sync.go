@@ -0,0 +1,99 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrSyncInProgress is returned by a Syncer when the group is already syncing.
+	ErrSyncInProgress = errors.New("sync already in progress")
+	// ErrUnknownGroup is returned by a Syncer when the requested group does not exist.
+	ErrUnknownGroup = errors.New("unknown sync group")
+
+	errUnauthorized = errors.New("missing or invalid bearer token")
+)
+
+// InvalidRequestError is returned by a Syncer when the sync request cannot be run.
+type InvalidRequestError struct {
+	Reason string
+}
+
+func (e *InvalidRequestError) Error() string {
+	return e.Reason
+}
+
+// SyncRequest selects what an on demand sync syncs. Empty fields keep the settings of the group.
+type SyncRequest struct {
+	// Group is the sync group to sync, required with more than one group
+	Group string `json:"group,omitempty"`
+	// Mode is full or selective
+	Mode string `json:"mode,omitempty"`
+	// Config lists the config sections synced by a selective sync, e.g. dns
+	Config []string `json:"config,omitempty"`
+	// Gravity lists the teleporter tables synced by a selective sync, e.g. adlist
+	Gravity []string `json:"gravity,omitempty"`
+}
+
+type SyncResponse struct {
+	ID    string `json:"id"`
+	Group string `json:"group"`
+}
+
+// Syncer starts on demand syncs.
+type Syncer interface {
+	// TriggerSync starts a sync in the background and returns its run ID and group.
+	TriggerSync(request SyncRequest) (*SyncResponse, error)
+}
+
+// authorize rejects requests without the bearer token of the server.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) syncHandler(w http.ResponseWriter, r *http.Request) {
+	request := SyncRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if mode := r.URL.Query().Get("mode"); mode != "" {
+		request.Mode = mode
+	}
+
+	response, err := s.syncer.TriggerSync(request)
+
+	var invalidRequestError *InvalidRequestError
+	switch {
+	case errors.Is(err, ErrSyncInProgress):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, ErrUnknownGroup):
+		writeError(w, http.StatusNotFound, err)
+	case errors.As(err, &invalidRequestError):
+		writeError(w, http.StatusBadRequest, err)
+	case err != nil:
+		log.Warn().Err(err).Msg("Failed to trigger sync")
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusAccepted, response)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
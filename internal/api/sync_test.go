@@ -0,0 +1,91 @@
+package api_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lovelaze/nebula-sync/internal/api"
+	apimock "github.com/lovelaze/nebula-sync/internal/mocks/api"
+	"github.com/lovelaze/nebula-sync/internal/sync"
+)
+
+func TestSyncHandler(t *testing.T) {
+	syncer := apimock.NewSyncer(t)
+	server := api.NewServer(map[string]*sync.State{"default": sync.NewState()}, syncer, "token")
+
+	syncer.EXPECT().TriggerSync(api.SyncRequest{Mode: "selective", Config: []string{"dns"}}).
+		Once().Return(&api.SyncResponse{ID: "abc", Group: "default"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/sync?mode=selective", strings.NewReader(`{"config":["dns"]}`))
+	req.Header.Set("Authorization", "Bearer token")
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.JSONEq(t, `{"id":"abc","group":"default"}`, resp.Body.String())
+}
+
+func TestSyncHandler_errors(t *testing.T) {
+	tests := map[error]int{
+		api.ErrSyncInProgress:                            http.StatusConflict,
+		fmt.Errorf("%w: garage", api.ErrUnknownGroup):    http.StatusNotFound,
+		&api.InvalidRequestError{Reason: "unknown mode"}: http.StatusBadRequest,
+		errors.New("failed"):                             http.StatusInternalServerError,
+	}
+
+	for err, status := range tests {
+		syncer := apimock.NewSyncer(t)
+		server := api.NewServer(map[string]*sync.State{"default": sync.NewState()}, syncer, "token")
+		syncer.EXPECT().TriggerSync(mock.Anything).Once().Return(nil, err)
+
+		resp := httptest.NewRecorder()
+		server.ServeHTTP(resp, syncRequest(""))
+		assert.Equal(t, status, resp.Code, err.Error())
+	}
+
+	server := api.NewServer(map[string]*sync.State{"default": sync.NewState()}, apimock.NewSyncer(t), "token")
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, syncRequest("{"))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	server = api.NewServer(map[string]*sync.State{"default": sync.NewState()}, nil, "token")
+	resp = httptest.NewRecorder()
+	server.ServeHTTP(resp, syncRequest(""))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestSyncHandler_unauthorized(t *testing.T) {
+	// the syncer fails the test if a sync is triggered
+	syncer := apimock.NewSyncer(t)
+
+	server := api.NewServer(map[string]*sync.State{"default": sync.NewState()}, syncer, "token")
+	for _, header := range []string{"", "Bearer wrong", "token", "Basic token"} {
+		req := httptest.NewRequest(http.MethodPost, "/sync", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+
+		resp := httptest.NewRecorder()
+		server.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code, header)
+	}
+
+	// without a token on demand syncs are disabled
+	server = api.NewServer(map[string]*sync.State{"default": sync.NewState()}, syncer, "")
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, syncRequest(""))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func syncRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/sync", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	return req
+}
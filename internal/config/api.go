@@ -1,5 +1,42 @@
 package config
 
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
 type API struct {
 	Enabled bool `default:"false" envconfig:"ENABLED"` // internal use only
+	// Token is the bearer token required by POST /sync, the endpoint is disabled without one
+	Token string `envconfig:"TOKEN"`
+}
+
+// loadAPI reads the API token from the file named by API_TOKEN_FILE, if set.
+func (c *Config) loadAPI() error {
+	if fileValue := os.Getenv("API_TOKEN_FILE"); len(fileValue) > 0 {
+		bytes, err := os.ReadFile(fileValue)
+		if err != nil {
+			return fmt.Errorf("read api token: %w", err)
+		}
+		c.API.Token = strings.TrimSpace(string(bytes))
+	}
+
+	return nil
+}
+
+// validateAPI fails if a token is set while no sync group is scheduled, since the API only runs alongside a schedule.
+func (c *Config) validateAPI() error {
+	if c.API.Token == "" {
+		return nil
+	}
+
+	for _, group := range c.SyncGroups() {
+		if group.Sync.Cron != nil || group.Sync.GravityCron != nil {
+			return nil
+		}
+	}
+
+	return errors.New("API_TOKEN requires CRON or GRAVITY_CRON, the API does not run without a schedule")
 }
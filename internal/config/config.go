@@ -2,28 +2,83 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 
 	"github.com/lovelaze/nebula-sync/internal/pihole/model"
 	"github.com/lovelaze/nebula-sync/internal/sync/filter"
+	"github.com/lovelaze/nebula-sync/internal/sync/merge"
+	"github.com/lovelaze/nebula-sync/internal/sync/transform"
 )
 
 type Config struct {
-	Primary  model.PiHole   `ignored:"true" required:"true" envconfig:"PRIMARY"`
-	Replicas []model.PiHole `ignored:"true" required:"true" envconfig:"REPLICAS"`
-	Client   *Client        `ignored:"true"`
-	Sync     *Sync          `ignored:"true"`
-	API      *API           `                               envconfig:"API"`
+	Primary    model.PiHole   `ignored:"true" required:"true" envconfig:"PRIMARY"`
+	Replicas   []model.PiHole `ignored:"true" required:"true" envconfig:"REPLICAS"`
+	Client     *Client        `ignored:"true"`
+	Sync       *Sync          `ignored:"true"`
+	API        *API           `                               envconfig:"API"`
+	GroupNames []string       `                               envconfig:"SYNC_GROUPS"`
+	Groups     []*Group       `ignored:"true"`
+
+	// prefix is prepended to the env vars of a sync group
+	prefix string
 }
 
+const (
+	// ModeSync writes the primary's settings to the replicas.
+	ModeSync = "sync"
+	// ModeDetect only reports replicas that drifted from the primary.
+	ModeDetect = "detect"
+)
+
+const (
+	// OverlapSkip drops a scheduled run while the previous one is still running.
+	OverlapSkip = "skip"
+	// OverlapDelay starts a scheduled run once the previous one has finished.
+	OverlapDelay = "delay"
+)
+
+const (
+	// GravityEngineTeleporter imports the gravity database of the primary as a whole.
+	GravityEngineTeleporter = "teleporter"
+	// GravityEngineRecords adds, updates and deletes the gravity entries of the replicas one by one.
+	GravityEngineRecords = "records"
+)
+
 type Sync struct {
-	FullSync        bool    `required:"true" envconfig:"FULL_SYNC"`
-	Cron            *string `                envconfig:"CRON"`
-	RunGravity      bool    `                envconfig:"RUN_GRAVITY" default:"false"`
-	GravitySettings *GravitySettings
-	ConfigSettings  *ConfigSettings  `                                                        ignored:"true"`
-	WebhookSettings *WebhookSettings `                                                        ignored:"true"`
+	FullSync         bool          `required:"true" envconfig:"FULL_SYNC"`
+	Cron             *string       `                envconfig:"CRON"`
+	RunGravity       bool          `                envconfig:"RUN_GRAVITY"      default:"false"`
+	DryRun           bool          `                envconfig:"DRY_RUN"          default:"false"`
+	Parallelism      int           `                envconfig:"SYNC_PARALLELISM" default:"1"`
+	BestEffort       bool          `                envconfig:"SYNC_BEST_EFFORT" default:"false"`
+	ForceSync        bool          `                envconfig:"FORCE_SYNC"       default:"false"`
+	StateFile        string        `                envconfig:"SYNC_STATE_FILE"`
+	Rollback         bool          `                envconfig:"SYNC_ROLLBACK"    default:"false"`
+	Verify           bool          `                envconfig:"SYNC_VERIFY"      default:"false"`
+	Canary           bool          `                envconfig:"SYNC_CANARY"      default:"false"`
+	CanaryReplica    string        `                envconfig:"SYNC_CANARY_REPLICA"`
+	CanaryDNSQuery   string        `                envconfig:"SYNC_CANARY_DNS_QUERY"`
+	TransformFile    string        `                envconfig:"SYNC_TRANSFORM_FILE"`
+	Mode             string        `                envconfig:"SYNC_MODE"        default:"sync"`
+	GravityCron      *string       `                envconfig:"GRAVITY_CRON"`
+	Rolling          bool          `                envconfig:"SYNC_ROLLING"           default:"false"`
+	RollingDelay     time.Duration `                envconfig:"SYNC_ROLLING_DELAY"     default:"0s"`
+	RollingTimeout   time.Duration `                envconfig:"SYNC_ROLLING_TIMEOUT"   default:"2m"`
+	RollingDNSQuery  string        `                envconfig:"SYNC_ROLLING_DNS_QUERY" default:"pi.hole"`
+	Overlap          string        `                envconfig:"SYNC_OVERLAP"           default:"skip"`
+	Timeout          time.Duration `                envconfig:"SYNC_TIMEOUT"           default:"0s"`
+	KeepSessions     bool          `                envconfig:"SYNC_KEEP_SESSIONS"     default:"false"`
+	GravityEngine    string        `                envconfig:"SYNC_GRAVITY_ENGINE"    default:"teleporter"`
+	GravityAdditive  bool          `                envconfig:"SYNC_GRAVITY_ADDITIVE"  default:"false"`
+	GravitySettings  *GravitySettings
+	ConfigSettings   *ConfigSettings    `                                                        ignored:"true"`
+	WebhookSettings  *WebhookSettings   `                                                        ignored:"true"`
+	ReplicaOverrides []*ReplicaOverride `                                                        ignored:"true"`
+	TransformRules   []*transform.Rule  `                                                        ignored:"true"`
+	MergeRules       []*merge.Rule      `                                                        ignored:"true"`
 }
 
 type GravitySettings struct {
@@ -50,29 +105,33 @@ type ConfigSettings struct {
 }
 
 type RawConfigSettings struct {
-	DNS             bool     `default:"false" envconfig:"SYNC_CONFIG_DNS"`
-	DNSInclude      []string `                envconfig:"SYNC_CONFIG_DNS_INCLUDE"`
-	DNSExclude      []string `                envconfig:"SYNC_CONFIG_DNS_EXCLUDE"`
-	DHCP            bool     `default:"false" envconfig:"SYNC_CONFIG_DHCP"`
-	DHCPInclude     []string `                envconfig:"SYNC_CONFIG_DHCP_INCLUDE"`
-	DHCPExclude     []string `                envconfig:"SYNC_CONFIG_DHCP_EXCLUDE"`
-	NTP             bool     `default:"false" envconfig:"SYNC_CONFIG_NTP"`
-	NTPInclude      []string `                envconfig:"SYNC_CONFIG_NTP_INCLUDE"`
-	NTPExclude      []string `                envconfig:"SYNC_CONFIG_NTP_EXCLUDE"`
-	Resolver        bool     `default:"false" envconfig:"SYNC_CONFIG_RESOLVER"`
-	ResolverInclude []string `                envconfig:"SYNC_CONFIG_RESOLVER_INCLUDE"`
-	ResolverExclude []string `                envconfig:"SYNC_CONFIG_RESOLVER_EXCLUDE"`
-	Database        bool     `default:"false" envconfig:"SYNC_CONFIG_DATABASE"`
-	DatabaseInclude []string `                envconfig:"SYNC_CONFIG_DATABASE_INCLUDE"`
-	DatabaseExclude []string `                envconfig:"SYNC_CONFIG_DATABASE_EXCLUDE"`
-	Webserver       bool     `default:"false"                                          ignored:"true"` // ignore for now
-	Files           bool     `default:"false"                                          ignored:"true"` // ignore for now
-	Misc            bool     `default:"false" envconfig:"SYNC_CONFIG_MISC"`
-	MiscInclude     []string `                envconfig:"SYNC_CONFIG_MISC_INCLUDE"`
-	MiscExclude     []string `                envconfig:"SYNC_CONFIG_MISC_EXCLUDE"`
-	Debug           bool     `default:"false" envconfig:"SYNC_CONFIG_DEBUG"`
-	DebugInclude    []string `                envconfig:"SYNC_CONFIG_DEBUG_INCLUDE"`
-	DebugExclude    []string `                envconfig:"SYNC_CONFIG_DEBUG_EXCLUDE"`
+	DNS              bool     `default:"false" envconfig:"SYNC_CONFIG_DNS"`
+	DNSInclude       []string `                envconfig:"SYNC_CONFIG_DNS_INCLUDE"`
+	DNSExclude       []string `                envconfig:"SYNC_CONFIG_DNS_EXCLUDE"`
+	DHCP             bool     `default:"false" envconfig:"SYNC_CONFIG_DHCP"`
+	DHCPInclude      []string `                envconfig:"SYNC_CONFIG_DHCP_INCLUDE"`
+	DHCPExclude      []string `                envconfig:"SYNC_CONFIG_DHCP_EXCLUDE"`
+	NTP              bool     `default:"false" envconfig:"SYNC_CONFIG_NTP"`
+	NTPInclude       []string `                envconfig:"SYNC_CONFIG_NTP_INCLUDE"`
+	NTPExclude       []string `                envconfig:"SYNC_CONFIG_NTP_EXCLUDE"`
+	Resolver         bool     `default:"false" envconfig:"SYNC_CONFIG_RESOLVER"`
+	ResolverInclude  []string `                envconfig:"SYNC_CONFIG_RESOLVER_INCLUDE"`
+	ResolverExclude  []string `                envconfig:"SYNC_CONFIG_RESOLVER_EXCLUDE"`
+	Database         bool     `default:"false" envconfig:"SYNC_CONFIG_DATABASE"`
+	DatabaseInclude  []string `                envconfig:"SYNC_CONFIG_DATABASE_INCLUDE"`
+	DatabaseExclude  []string `                envconfig:"SYNC_CONFIG_DATABASE_EXCLUDE"`
+	Webserver        bool     `default:"false" envconfig:"SYNC_CONFIG_WEBSERVER"`
+	WebserverInclude []string `                envconfig:"SYNC_CONFIG_WEBSERVER_INCLUDE"`
+	WebserverExclude []string `                envconfig:"SYNC_CONFIG_WEBSERVER_EXCLUDE"`
+	Files            bool     `default:"false" envconfig:"SYNC_CONFIG_FILES"`
+	FilesInclude     []string `                envconfig:"SYNC_CONFIG_FILES_INCLUDE"`
+	FilesExclude     []string `                envconfig:"SYNC_CONFIG_FILES_EXCLUDE"`
+	Misc             bool     `default:"false" envconfig:"SYNC_CONFIG_MISC"`
+	MiscInclude      []string `                envconfig:"SYNC_CONFIG_MISC_INCLUDE"`
+	MiscExclude      []string `                envconfig:"SYNC_CONFIG_MISC_EXCLUDE"`
+	Debug            bool     `default:"false" envconfig:"SYNC_CONFIG_DEBUG"`
+	DebugInclude     []string `                envconfig:"SYNC_CONFIG_DEBUG_INCLUDE"`
+	DebugExclude     []string `                envconfig:"SYNC_CONFIG_DEBUG_EXCLUDE"`
 }
 
 func (raw *RawConfigSettings) Validate() error {
@@ -80,6 +139,9 @@ func (raw *RawConfigSettings) Validate() error {
 		if include != nil && exclude != nil {
 			return fmt.Errorf("%s: INCLUDE/EXCLUDE must be mutually exclusive", name)
 		}
+		if err := filter.Validate(append(slices.Clone(include), exclude...)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
 		return nil
 	}
 
@@ -98,6 +160,12 @@ func (raw *RawConfigSettings) Validate() error {
 	if err := exclusive("database", raw.DatabaseInclude, raw.DatabaseExclude); err != nil {
 		return err
 	}
+	if err := exclusive("webserver", raw.WebserverInclude, raw.WebserverExclude); err != nil {
+		return err
+	}
+	if err := exclusive("files", raw.FilesInclude, raw.FilesExclude); err != nil {
+		return err
+	}
 	if err := exclusive("misc", raw.MiscInclude, raw.MiscExclude); err != nil {
 		return err
 	}
@@ -116,8 +184,8 @@ func (raw *RawConfigSettings) Parse() (*ConfigSettings, error) {
 		NTP:       NewConfigSetting(raw.NTP, raw.NTPInclude, raw.NTPExclude),
 		Resolver:  NewConfigSetting(raw.Resolver, raw.ResolverInclude, raw.ResolverExclude),
 		Database:  NewConfigSetting(raw.Database, raw.DatabaseInclude, raw.DatabaseExclude),
-		Webserver: NewConfigSetting(raw.Webserver, nil, nil),
-		Files:     NewConfigSetting(raw.Files, nil, nil),
+		Webserver: NewConfigSetting(raw.Webserver, raw.WebserverInclude, raw.WebserverExclude),
+		Files:     NewConfigSetting(raw.Files, raw.FilesInclude, raw.FilesExclude),
 		Misc:      NewConfigSetting(raw.Misc, raw.MiscInclude, raw.MiscExclude),
 		Debug:     NewConfigSetting(raw.Debug, raw.DebugInclude, raw.DebugExclude),
 	}, nil
@@ -126,6 +194,8 @@ func (raw *RawConfigSettings) Parse() (*ConfigSettings, error) {
 type ConfigSetting struct {
 	Enabled bool
 	Filter  *ConfigFilter
+	// Exclude lists keys removed after Filter was applied
+	Exclude []string
 }
 
 type ConfigFilter struct {
@@ -163,11 +233,28 @@ func (c *Config) Load() error {
 		return err
 	}
 
-	if err := c.loadTargets(); err != nil {
+	if err := c.loadClient(); err != nil {
 		return err
 	}
 
-	if err := c.loadClient(); err != nil {
+	if err := c.loadAPI(); err != nil {
+		return err
+	}
+
+	if len(c.GroupNames) > 0 {
+		if err := c.loadGroups(); err != nil {
+			return err
+		}
+	} else if err := c.loadGroup(); err != nil {
+		return err
+	}
+
+	return c.validateAPI()
+}
+
+// loadGroup loads the targets and sync settings of a single sync group.
+func (c *Config) loadGroup() error {
+	if err := c.loadTargets(); err != nil {
 		return err
 	}
 
@@ -175,27 +262,75 @@ func (c *Config) Load() error {
 		return err
 	}
 
+	if err := c.loadReplicaOverrides(); err != nil {
+		return err
+	}
+
 	return c.loadWebhookSettings()
 }
 
+// env returns the env var key, prefixed if the config belongs to a named sync group.
+func (c *Config) env(key string) string {
+	if c.prefix == "" {
+		return key
+	}
+	return c.prefix + "_" + key
+}
+
 func (c *Config) loadSync() error {
 	sync := Sync{}
-	if err := envconfig.Process("", &sync); err != nil {
+	if err := envconfig.Process(c.prefix, &sync); err != nil {
 		return fmt.Errorf("sync env vars: %w", err)
 	}
 
-	if err := sync.loadConfigSettings(); err != nil {
+	// nested settings are only looked up unprefixed, read the group's own values on top
+	if c.prefix != "" {
+		if err := envconfig.Process(c.prefix, sync.GravitySettings); err != nil {
+			return fmt.Errorf("gravity settings env vars: %w", err)
+		}
+	}
+
+	if sync.Mode != ModeSync && sync.Mode != ModeDetect {
+		return fmt.Errorf("invalid sync mode %q, must be %s or %s", sync.Mode, ModeSync, ModeDetect)
+	}
+
+	if sync.Overlap != OverlapSkip && sync.Overlap != OverlapDelay {
+		return fmt.Errorf("invalid sync overlap %q, must be %s or %s", sync.Overlap, OverlapSkip, OverlapDelay)
+	}
+
+	if sync.GravityEngine != GravityEngineTeleporter && sync.GravityEngine != GravityEngineRecords {
+		return fmt.Errorf("invalid gravity engine %q, must be %s or %s",
+			sync.GravityEngine, GravityEngineTeleporter, GravityEngineRecords)
+	}
+
+	if sync.GravityAdditive && sync.GravityEngine != GravityEngineRecords {
+		return fmt.Errorf("additive gravity sync requires the %s gravity engine", GravityEngineRecords)
+	}
+
+	if err := sync.loadConfigSettings(c.prefix); err != nil {
 		return fmt.Errorf("load config settings: %w", err)
 	}
 
+	if err := sync.loadMergeRules(c.prefix); err != nil {
+		return fmt.Errorf("load merge settings: %w", err)
+	}
+
+	if sync.TransformFile != "" {
+		rules, err := transform.Load(sync.TransformFile)
+		if err != nil {
+			return err
+		}
+		sync.TransformRules = rules
+	}
+
 	c.Sync = &sync
 	return nil
 }
 
-func (s *Sync) loadConfigSettings() error {
+func (s *Sync) loadConfigSettings(prefix string) error {
 	raw := RawConfigSettings{}
 
-	if err := envconfig.Process("", &raw); err != nil {
+	if err := envconfig.Process(prefix, &raw); err != nil {
 		return fmt.Errorf("config settings env vars: %w", err)
 	}
 
@@ -217,7 +352,7 @@ func (s *Sync) String() string {
 }
 
 func (a *API) String() string {
-	return fmt.Sprintf("%+v", *a)
+	return fmt.Sprintf("{Enabled:%t Token:%t}", a.Enabled, a.Token != "")
 }
 
 func (gs *GravitySettings) String() string {
@@ -1,12 +1,16 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/lovelaze/nebula-sync/internal/sync/filter"
+	"github.com/lovelaze/nebula-sync/internal/sync/merge"
 )
 
 func TestConfig_Load(t *testing.T) {
@@ -25,8 +29,49 @@ func TestConfig_Load(t *testing.T) {
 	assert.Equal(t, "http://localhost:1338", conf.Replicas[0].URL.String())
 	assert.Equal(t, "qwerty", conf.Replicas[0].Password)
 	assert.False(t, conf.Sync.FullSync)
+	assert.Equal(t, 1, conf.Sync.Parallelism)
+	assert.Equal(t, ModeSync, conf.Sync.Mode)
+	assert.False(t, conf.Sync.Rolling)
+	assert.Equal(t, 2*time.Minute, conf.Sync.RollingTimeout)
+	assert.Equal(t, "pi.hole", conf.Sync.RollingDNSQuery)
+	assert.Equal(t, OverlapSkip, conf.Sync.Overlap)
+	assert.Zero(t, conf.Sync.Timeout)
+	assert.False(t, conf.Sync.KeepSessions)
+	assert.Equal(t, GravityEngineTeleporter, conf.Sync.GravityEngine)
+	assert.False(t, conf.Sync.GravityAdditive)
 	assert.Equal(t, "POST", conf.Sync.WebhookSettings.Success.Method)
+	assert.Equal(t, "POST", conf.Sync.WebhookSettings.Drift.Method)
 	assert.Equal(t, "POST", conf.Sync.WebhookSettings.Failure.Method)
+	assert.Empty(t, conf.API.Token)
+}
+
+func TestConfig_Load_apiTokenFile(t *testing.T) {
+	conf := Config{}
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("secret\n"), 0o600))
+
+	t.Setenv("PRIMARY", "http://localhost:1337|asdf")
+	t.Setenv("REPLICAS", "http://localhost:1338|qwerty")
+	t.Setenv("FULL_SYNC", "true")
+	t.Setenv("CRON", "* * * * *")
+	t.Setenv("API_TOKEN", "ignored")
+	t.Setenv("API_TOKEN_FILE", path)
+
+	require.NoError(t, conf.Load())
+	assert.Equal(t, "secret", conf.API.Token)
+	assert.NotContains(t, conf.API.String(), "secret")
+}
+
+func TestConfig_Load_apiTokenWithoutCron(t *testing.T) {
+	conf := Config{}
+
+	t.Setenv("PRIMARY", "http://localhost:1337|asdf")
+	t.Setenv("REPLICAS", "http://localhost:1338|qwerty")
+	t.Setenv("FULL_SYNC", "true")
+	t.Setenv("API_TOKEN", "secret")
+
+	require.ErrorContains(t, conf.Load(), "API_TOKEN requires CRON")
 }
 
 func TestConfig_loadSync(t *testing.T) {
@@ -35,13 +80,23 @@ func TestConfig_loadSync(t *testing.T) {
 
 	t.Setenv("FULL_SYNC", "true")
 	t.Setenv("CRON", "* * * * *")
+	t.Setenv("GRAVITY_CRON", "0 3 * * 0")
 	t.Setenv("RUN_GRAVITY", "true")
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("SYNC_PARALLELISM", "4")
+	t.Setenv("SYNC_ROLLBACK", "true")
+	t.Setenv("SYNC_VERIFY", "true")
+	t.Setenv("SYNC_CANARY", "true")
+	t.Setenv("SYNC_CANARY_REPLICA", "http://localhost:1338")
+	t.Setenv("SYNC_CANARY_DNS_QUERY", "pi.hole")
 
 	t.Setenv("SYNC_CONFIG_DNS", "true")
 	t.Setenv("SYNC_CONFIG_DHCP", "true")
 	t.Setenv("SYNC_CONFIG_NTP", "true")
 	t.Setenv("SYNC_CONFIG_RESOLVER", "true")
 	t.Setenv("SYNC_CONFIG_DATABASE", "true")
+	t.Setenv("SYNC_CONFIG_WEBSERVER", "true")
+	t.Setenv("SYNC_CONFIG_FILES", "true")
 	t.Setenv("SYNC_CONFIG_MISC", "true")
 	t.Setenv("SYNC_CONFIG_DEBUG", "true")
 
@@ -59,7 +114,15 @@ func TestConfig_loadSync(t *testing.T) {
 
 	assert.True(t, conf.Sync.FullSync)
 	assert.Equal(t, "* * * * *", *conf.Sync.Cron)
+	assert.Equal(t, "0 3 * * 0", *conf.Sync.GravityCron)
 	assert.True(t, conf.Sync.RunGravity)
+	assert.True(t, conf.Sync.DryRun)
+	assert.Equal(t, 4, conf.Sync.Parallelism)
+	assert.True(t, conf.Sync.Rollback)
+	assert.True(t, conf.Sync.Verify)
+	assert.True(t, conf.Sync.Canary)
+	assert.Equal(t, "http://localhost:1338", conf.Sync.CanaryReplica)
+	assert.Equal(t, "pi.hole", conf.Sync.CanaryDNSQuery)
 
 	assert.NotNil(t, conf.Sync.ConfigSettings)
 	assert.NotNil(t, conf.Sync.GravitySettings)
@@ -69,6 +132,8 @@ func TestConfig_loadSync(t *testing.T) {
 	assert.True(t, conf.Sync.ConfigSettings.NTP.Enabled)
 	assert.True(t, conf.Sync.ConfigSettings.Resolver.Enabled)
 	assert.True(t, conf.Sync.ConfigSettings.Database.Enabled)
+	assert.True(t, conf.Sync.ConfigSettings.Webserver.Enabled)
+	assert.True(t, conf.Sync.ConfigSettings.Files.Enabled)
 	assert.True(t, conf.Sync.ConfigSettings.Misc.Enabled)
 	assert.True(t, conf.Sync.ConfigSettings.Debug.Enabled)
 
@@ -119,9 +184,11 @@ func TestRawConfig_Parse_Include(t *testing.T) {
 	t.Setenv("SYNC_CONFIG_DATABASE_INCLUDE", "key9,key10")
 	t.Setenv("SYNC_CONFIG_MISC_INCLUDE", "key11,key12")
 	t.Setenv("SYNC_CONFIG_DEBUG_INCLUDE", "key13,key14")
+	t.Setenv("SYNC_CONFIG_WEBSERVER_INCLUDE", "key15,key16")
+	t.Setenv("SYNC_CONFIG_FILES_INCLUDE", "key17,key18")
 
 	sync := Sync{}
-	require.NoError(t, sync.loadConfigSettings())
+	require.NoError(t, sync.loadConfigSettings(""))
 
 	settings := sync.ConfigSettings
 
@@ -139,6 +206,10 @@ func TestRawConfig_Parse_Include(t *testing.T) {
 	assert.Equal(t, []string{"key11", "key12"}, settings.Misc.Filter.Keys)
 	assert.Equal(t, filter.Include, settings.Debug.Filter.Type)
 	assert.Equal(t, []string{"key13", "key14"}, settings.Debug.Filter.Keys)
+	assert.Equal(t, filter.Include, settings.Webserver.Filter.Type)
+	assert.Equal(t, []string{"key15", "key16"}, settings.Webserver.Filter.Keys)
+	assert.Equal(t, filter.Include, settings.Files.Filter.Type)
+	assert.Equal(t, []string{"key17", "key18"}, settings.Files.Filter.Keys)
 }
 
 func TestRawConfig_Parse_Exclude(t *testing.T) {
@@ -149,9 +220,11 @@ func TestRawConfig_Parse_Exclude(t *testing.T) {
 	t.Setenv("SYNC_CONFIG_DATABASE_EXCLUDE", "key9,key10")
 	t.Setenv("SYNC_CONFIG_MISC_EXCLUDE", "key11,key12")
 	t.Setenv("SYNC_CONFIG_DEBUG_EXCLUDE", "key13,key14")
+	t.Setenv("SYNC_CONFIG_WEBSERVER_EXCLUDE", "key15,key16")
+	t.Setenv("SYNC_CONFIG_FILES_EXCLUDE", "key17,key18")
 
 	sync := Sync{}
-	require.NoError(t, sync.loadConfigSettings())
+	require.NoError(t, sync.loadConfigSettings(""))
 
 	settings := sync.ConfigSettings
 
@@ -169,6 +242,10 @@ func TestRawConfig_Parse_Exclude(t *testing.T) {
 	assert.Equal(t, []string{"key11", "key12"}, settings.Misc.Filter.Keys)
 	assert.Equal(t, filter.Exclude, settings.Debug.Filter.Type)
 	assert.Equal(t, []string{"key13", "key14"}, settings.Debug.Filter.Keys)
+	assert.Equal(t, filter.Exclude, settings.Webserver.Filter.Type)
+	assert.Equal(t, []string{"key15", "key16"}, settings.Webserver.Filter.Keys)
+	assert.Equal(t, filter.Exclude, settings.Files.Filter.Type)
+	assert.Equal(t, []string{"key17", "key18"}, settings.Files.Filter.Keys)
 }
 
 func TestConfig_NewConfigSetting(t *testing.T) {
@@ -192,3 +269,94 @@ func TestConfig_NewConfigSetting(t *testing.T) {
 	assert.Equal(t, filter.Exclude, exclude.Filter.Type)
 	assert.Equal(t, []string{"key1", "key2"}, exclude.Filter.Keys)
 }
+
+func TestConfig_loadSync_transformFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transform.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"key": "dhcp.router", "set": "{{ .Host }}"}]`), 0o600))
+
+	t.Setenv("FULL_SYNC", "true")
+	t.Setenv("SYNC_TRANSFORM_FILE", path)
+
+	conf := Config{}
+	require.NoError(t, conf.loadSync())
+
+	require.Len(t, conf.Sync.TransformRules, 1)
+	assert.Equal(t, "dhcp.router", conf.Sync.TransformRules[0].Key)
+}
+
+func TestConfig_loadSync_transformFileMissing(t *testing.T) {
+	t.Setenv("FULL_SYNC", "true")
+	t.Setenv("SYNC_TRANSFORM_FILE", filepath.Join(t.TempDir(), "missing.json"))
+
+	conf := Config{}
+	require.ErrorContains(t, conf.loadSync(), "read transform file")
+}
+
+func TestRawConfig_Validate_InvalidSelector(t *testing.T) {
+	settings := RawConfigSettings{DNSInclude: []string{"re:("}}
+	assert.ErrorContains(t, settings.Validate(), "dns: invalid config filter")
+
+	settings = RawConfigSettings{MiscExclude: []string{"dnsmasq_*"}, DHCPInclude: []string{`re:^(start|end)$`}}
+	assert.NoError(t, settings.Validate())
+}
+
+func TestRawMergeSettings_Parse(t *testing.T) {
+	raw := RawMergeSettings{
+		DNSHosts:         merge.PrimaryWinsByKey,
+		DHCPHostsInclude: []string{"*,nas*"},
+	}
+
+	rules, err := raw.Parse()
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "dns.hosts", rules[0].Key)
+	assert.Equal(t, merge.PrimaryWinsByKey, rules[0].Strategy)
+	assert.Equal(t, "dhcp.hosts", rules[1].Key)
+	assert.Equal(t, merge.Replace, rules[1].Strategy)
+
+	raw = RawMergeSettings{DNSUpstreams: "append"}
+	_, err = raw.Parse()
+	assert.ErrorContains(t, err, "unknown merge strategy")
+}
+
+func TestConfig_loadSync_mode(t *testing.T) {
+	t.Setenv("FULL_SYNC", "true")
+	t.Setenv("SYNC_MODE", "detect")
+
+	conf := Config{}
+	require.NoError(t, conf.loadSync())
+	assert.Equal(t, ModeDetect, conf.Sync.Mode)
+
+	t.Setenv("SYNC_MODE", "audit")
+	assert.ErrorContains(t, conf.loadSync(), "invalid sync mode")
+}
+
+func TestConfig_loadSync_overlap(t *testing.T) {
+	t.Setenv("FULL_SYNC", "true")
+	t.Setenv("SYNC_OVERLAP", "delay")
+	t.Setenv("SYNC_TIMEOUT", "90s")
+
+	conf := Config{}
+	require.NoError(t, conf.loadSync())
+	assert.Equal(t, OverlapDelay, conf.Sync.Overlap)
+	assert.Equal(t, 90*time.Second, conf.Sync.Timeout)
+
+	t.Setenv("SYNC_OVERLAP", "queue")
+	assert.ErrorContains(t, conf.loadSync(), "invalid sync overlap")
+}
+
+func TestConfig_loadSync_gravityEngine(t *testing.T) {
+	t.Setenv("FULL_SYNC", "true")
+	t.Setenv("SYNC_GRAVITY_ADDITIVE", "true")
+
+	conf := Config{}
+	assert.ErrorContains(t, conf.loadSync(), "additive gravity sync requires the records gravity engine")
+
+	t.Setenv("SYNC_GRAVITY_ENGINE", "records")
+	require.NoError(t, conf.loadSync())
+	assert.Equal(t, GravityEngineRecords, conf.Sync.GravityEngine)
+	assert.True(t, conf.Sync.GravityAdditive)
+
+	t.Setenv("SYNC_GRAVITY_ENGINE", "sql")
+	assert.ErrorContains(t, conf.loadSync(), "invalid gravity engine")
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+)
+
+// DefaultGroup is the name of the only sync group when SYNC_GROUPS is not set.
+const DefaultGroup = "default"
+
+var groupNamePattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// Group is a named set of a primary and its replicas, synced with its own settings.
+type Group struct {
+	Name     string
+	Primary  model.PiHole
+	Replicas []model.PiHole
+	Sync     *Sync
+}
+
+// loadGroups loads every group listed in SYNC_GROUPS from env vars prefixed with the upper case group name.
+func (c *Config) loadGroups() error {
+	names, err := groupNames(c.GroupNames)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		group := Config{prefix: strings.ToUpper(name)}
+		if err := group.loadGroup(); err != nil {
+			return fmt.Errorf("sync group %s: %w", name, err)
+		}
+
+		c.Groups = append(c.Groups, &Group{
+			Name:     name,
+			Primary:  group.Primary,
+			Replicas: group.Replicas,
+			Sync:     group.Sync,
+		})
+	}
+
+	return nil
+}
+
+// groupNames validates the group names, they must be usable as unique env var prefixes.
+func groupNames(values []string) ([]string, error) {
+	names := make([]string, 0, len(values))
+	prefixes := make(map[string]bool)
+
+	for _, value := range values {
+		name := strings.TrimSpace(value)
+		if !groupNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid sync group name %q: only letters and digits are allowed", name)
+		}
+
+		prefix := strings.ToUpper(name)
+		if prefixes[prefix] {
+			return nil, fmt.Errorf("duplicate sync group name %q", name)
+		}
+		prefixes[prefix] = true
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// SyncGroups returns the configured sync groups, or a single default group built from the top level settings.
+func (c *Config) SyncGroups() []*Group {
+	if len(c.Groups) > 0 {
+		return c.Groups
+	}
+
+	return []*Group{{
+		Name:     DefaultGroup,
+		Primary:  c.Primary,
+		Replicas: c.Replicas,
+		Sync:     c.Sync,
+	}}
+}
+
+func (g *Group) String() string {
+	replicas := make([]string, 0, len(g.Replicas))
+	for i := range g.Replicas {
+		replicas = append(replicas, g.Replicas[i].String())
+	}
+
+	cron := ""
+	if g.Sync != nil && g.Sync.Cron != nil {
+		cron = *g.Sync.Cron
+	}
+
+	return fmt.Sprintf("{Name:%s Primary:%s Replicas:[%s] Cron:%s}",
+		g.Name, g.Primary.String(), strings.Join(replicas, " "), cron)
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Load_groups(t *testing.T) {
+	conf := Config{}
+
+	t.Setenv("SYNC_GROUPS", "home,office")
+	t.Setenv("FULL_SYNC", "false")
+	t.Setenv("SYNC_CONFIG_DNS", "true")
+
+	t.Setenv("HOME_PRIMARY", "http://home1|asdf")
+	t.Setenv("HOME_REPLICAS", "http://home2|qwerty")
+	t.Setenv("HOME_CRON", "* * * * *")
+	t.Setenv("HOME_SYNC_GRAVITY_GROUP", "true")
+	t.Setenv("HOME_WEBHOOK_SYNC_FAILURE_URL", "http://home/webhook")
+	t.Setenv("HOME_REPLICA_1_SYNC_CONFIG_DHCP", "false")
+
+	t.Setenv("OFFICE_PRIMARY", "http://office1|asdf")
+	t.Setenv("OFFICE_REPLICAS", "http://office2|qwerty,http://office3|foobar")
+	t.Setenv("OFFICE_FULL_SYNC", "true")
+	t.Setenv("OFFICE_SYNC_CONFIG_DNS", "false")
+
+	require.NoError(t, conf.Load())
+
+	groups := conf.SyncGroups()
+	require.Len(t, groups, 2)
+
+	home := groups[0]
+	assert.Equal(t, "home", home.Name)
+	assert.Equal(t, "http://home1", home.Primary.URL.String())
+	require.Len(t, home.Replicas, 1)
+	assert.False(t, home.Sync.FullSync)
+	require.NotNil(t, home.Sync.Cron)
+	assert.Equal(t, "* * * * *", *home.Sync.Cron)
+	assert.True(t, home.Sync.ConfigSettings.DNS.Enabled)
+	assert.True(t, home.Sync.GravitySettings.Group)
+	assert.Equal(t, "http://home/webhook", home.Sync.WebhookSettings.Failure.URL)
+	require.NotNil(t, home.Sync.ReplicaOverrides[0].DHCP)
+
+	office := groups[1]
+	assert.Equal(t, "office", office.Name)
+	assert.Equal(t, "http://office1", office.Primary.URL.String())
+	require.Len(t, office.Replicas, 2)
+	assert.True(t, office.Sync.FullSync)
+	assert.Nil(t, office.Sync.Cron)
+	assert.False(t, office.Sync.ConfigSettings.DNS.Enabled)
+	assert.False(t, office.Sync.GravitySettings.Group)
+	assert.Empty(t, office.Sync.WebhookSettings.Failure.URL)
+	assert.Nil(t, office.Sync.ReplicaOverrides[0].DHCP)
+
+	assert.Contains(t, conf.String(), "{Name:home Primary:{URL:http://home1} Replicas:[{URL:http://home2}] Cron:* * * * *}")
+	assert.Contains(t, conf.String(),
+		"{Name:office Primary:{URL:http://office1} Replicas:[{URL:http://office2} {URL:http://office3}] Cron:}")
+}
+
+func TestConfig_Load_groupsMissingPrimary(t *testing.T) {
+	conf := Config{}
+
+	t.Setenv("SYNC_GROUPS", "home")
+	t.Setenv("FULL_SYNC", "true")
+	t.Setenv("PRIMARY", "http://localhost:1337|asdf")
+	t.Setenv("HOME_REPLICAS", "http://home2|qwerty")
+
+	require.EqualError(t, conf.Load(), "sync group home: missing required env: HOME_PRIMARY/HOME_PRIMARY_FILE")
+}
+
+func TestConfig_Load_groupsInvalidName(t *testing.T) {
+	conf := Config{}
+
+	t.Setenv("SYNC_GROUPS", "home,home-lab")
+
+	require.ErrorContains(t, conf.Load(), `invalid sync group name "home-lab"`)
+}
+
+func TestConfig_SyncGroups_default(t *testing.T) {
+	conf := Config{Sync: &Sync{}}
+
+	groups := conf.SyncGroups()
+
+	require.Len(t, groups, 1)
+	assert.Equal(t, DefaultGroup, groups[0].Name)
+	assert.Same(t, conf.Sync, groups[0].Sync)
+}
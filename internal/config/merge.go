@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/lovelaze/nebula-sync/internal/sync/merge"
+)
+
+// RawMergeSettings holds the element rules of the array config values that support them.
+type RawMergeSettings struct {
+	DNSHosts               merge.Strategy `envconfig:"SYNC_CONFIG_DNS_HOSTS_MERGE"`
+	DNSHostsInclude        []string       `envconfig:"SYNC_CONFIG_DNS_HOSTS_INCLUDE"`
+	DNSHostsExclude        []string       `envconfig:"SYNC_CONFIG_DNS_HOSTS_EXCLUDE"`
+	DNSCNAMERecords        merge.Strategy `envconfig:"SYNC_CONFIG_DNS_CNAME_RECORDS_MERGE"`
+	DNSCNAMERecordsInclude []string       `envconfig:"SYNC_CONFIG_DNS_CNAME_RECORDS_INCLUDE"`
+	DNSCNAMERecordsExclude []string       `envconfig:"SYNC_CONFIG_DNS_CNAME_RECORDS_EXCLUDE"`
+	DNSUpstreams           merge.Strategy `envconfig:"SYNC_CONFIG_DNS_UPSTREAMS_MERGE"`
+	DNSUpstreamsInclude    []string       `envconfig:"SYNC_CONFIG_DNS_UPSTREAMS_INCLUDE"`
+	DNSUpstreamsExclude    []string       `envconfig:"SYNC_CONFIG_DNS_UPSTREAMS_EXCLUDE"`
+	DHCPHosts              merge.Strategy `envconfig:"SYNC_CONFIG_DHCP_HOSTS_MERGE"`
+	DHCPHostsInclude       []string       `envconfig:"SYNC_CONFIG_DHCP_HOSTS_INCLUDE"`
+	DHCPHostsExclude       []string       `envconfig:"SYNC_CONFIG_DHCP_HOSTS_EXCLUDE"`
+}
+
+// Parse returns a rule for every array with at least one setting.
+func (raw *RawMergeSettings) Parse() ([]*merge.Rule, error) {
+	settings := []struct {
+		key      string
+		strategy merge.Strategy
+		include  []string
+		exclude  []string
+	}{
+		{"dns.hosts", raw.DNSHosts, raw.DNSHostsInclude, raw.DNSHostsExclude},
+		{"dns.cnameRecords", raw.DNSCNAMERecords, raw.DNSCNAMERecordsInclude, raw.DNSCNAMERecordsExclude},
+		{"dns.upstreams", raw.DNSUpstreams, raw.DNSUpstreamsInclude, raw.DNSUpstreamsExclude},
+		{"dhcp.hosts", raw.DHCPHosts, raw.DHCPHostsInclude, raw.DHCPHostsExclude},
+	}
+
+	var rules []*merge.Rule
+	for _, setting := range settings {
+		if setting.strategy == "" && setting.include == nil && setting.exclude == nil {
+			continue
+		}
+
+		rule, err := merge.NewRule(setting.key, setting.strategy, setting.include, setting.exclude)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (s *Sync) loadMergeRules(prefix string) error {
+	raw := RawMergeSettings{}
+
+	if err := envconfig.Process(prefix, &raw); err != nil {
+		return fmt.Errorf("merge settings env vars: %w", err)
+	}
+
+	rules, err := raw.Parse()
+	if err != nil {
+		return err
+	}
+
+	s.MergeRules = rules
+	return nil
+}
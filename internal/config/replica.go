@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/lovelaze/nebula-sync/internal/sync/filter"
+)
+
+// ReplicaOverride changes the sync settings of a single replica.
+// Sections and gravity settings that are set replace the shared value, exclude keys are removed in addition to the shared filter.
+type ReplicaOverride struct {
+	DNS              *bool    `envconfig:"SYNC_CONFIG_DNS"`
+	DNSExclude       []string `envconfig:"SYNC_CONFIG_DNS_EXCLUDE"`
+	DHCP             *bool    `envconfig:"SYNC_CONFIG_DHCP"`
+	DHCPExclude      []string `envconfig:"SYNC_CONFIG_DHCP_EXCLUDE"`
+	NTP              *bool    `envconfig:"SYNC_CONFIG_NTP"`
+	NTPExclude       []string `envconfig:"SYNC_CONFIG_NTP_EXCLUDE"`
+	Resolver         *bool    `envconfig:"SYNC_CONFIG_RESOLVER"`
+	ResolverExclude  []string `envconfig:"SYNC_CONFIG_RESOLVER_EXCLUDE"`
+	Database         *bool    `envconfig:"SYNC_CONFIG_DATABASE"`
+	DatabaseExclude  []string `envconfig:"SYNC_CONFIG_DATABASE_EXCLUDE"`
+	Webserver        *bool    `envconfig:"SYNC_CONFIG_WEBSERVER"`
+	WebserverExclude []string `envconfig:"SYNC_CONFIG_WEBSERVER_EXCLUDE"`
+	Files            *bool    `envconfig:"SYNC_CONFIG_FILES"`
+	FilesExclude     []string `envconfig:"SYNC_CONFIG_FILES_EXCLUDE"`
+	Misc             *bool    `envconfig:"SYNC_CONFIG_MISC"`
+	MiscExclude      []string `envconfig:"SYNC_CONFIG_MISC_EXCLUDE"`
+	Debug            *bool    `envconfig:"SYNC_CONFIG_DEBUG"`
+	DebugExclude     []string `envconfig:"SYNC_CONFIG_DEBUG_EXCLUDE"`
+
+	DHCPLeases        *bool `envconfig:"SYNC_GRAVITY_DHCP_LEASES"`
+	Group             *bool `envconfig:"SYNC_GRAVITY_GROUP"`
+	Adlist            *bool `envconfig:"SYNC_GRAVITY_AD_LIST"`
+	AdlistByGroup     *bool `envconfig:"SYNC_GRAVITY_AD_LIST_BY_GROUP"`
+	Domainlist        *bool `envconfig:"SYNC_GRAVITY_DOMAIN_LIST"`
+	DomainlistByGroup *bool `envconfig:"SYNC_GRAVITY_DOMAIN_LIST_BY_GROUP"`
+	Client            *bool `envconfig:"SYNC_GRAVITY_CLIENT"`
+	ClientByGroup     *bool `envconfig:"SYNC_GRAVITY_CLIENT_BY_GROUP"`
+}
+
+// loadReplicaOverrides reads the overrides of every replica from REPLICA_<n>_ prefixed env vars, n starting at 1.
+func (c *Config) loadReplicaOverrides() error {
+	overrides := make([]*ReplicaOverride, len(c.Replicas))
+
+	for i := range c.Replicas {
+		override := ReplicaOverride{}
+		if err := processExact(c.env(fmt.Sprintf("REPLICA_%d", i+1)), &override); err != nil {
+			return fmt.Errorf("replica %d override env vars: %w", i+1, err)
+		}
+		if err := override.validate(); err != nil {
+			return fmt.Errorf("replica %d override: %w", i+1, err)
+		}
+		overrides[i] = &override
+	}
+
+	c.Sync.ReplicaOverrides = overrides
+	return nil
+}
+
+// processExact is like envconfig.Process but drops values read from the unprefixed fallback envconfig uses for
+// tagged fields, so a shared setting is never mistaken for an override.
+func processExact(prefix string, spec any) error {
+	if err := envconfig.Process(prefix, spec); err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(spec).Elem()
+	for i := range value.NumField() {
+		key := prefix + "_" + value.Type().Field(i).Tag.Get("envconfig")
+		if _, exists := os.LookupEnv(key); !exists {
+			value.Field(i).SetZero()
+		}
+	}
+
+	return nil
+}
+
+func (o *ReplicaOverride) validate() error {
+	excludes := [][]string{
+		o.DNSExclude, o.DHCPExclude, o.NTPExclude, o.ResolverExclude, o.DatabaseExclude,
+		o.WebserverExclude, o.FilesExclude, o.MiscExclude, o.DebugExclude,
+	}
+	for _, exclude := range excludes {
+		if err := filter.Validate(exclude); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyConfig returns a copy of settings with the override applied.
+func (o *ReplicaOverride) ApplyConfig(settings *ConfigSettings) *ConfigSettings {
+	if o == nil || settings == nil {
+		return settings
+	}
+
+	return &ConfigSettings{
+		DNS:       overrideSetting(settings.DNS, o.DNS, o.DNSExclude),
+		DHCP:      overrideSetting(settings.DHCP, o.DHCP, o.DHCPExclude),
+		NTP:       overrideSetting(settings.NTP, o.NTP, o.NTPExclude),
+		Resolver:  overrideSetting(settings.Resolver, o.Resolver, o.ResolverExclude),
+		Database:  overrideSetting(settings.Database, o.Database, o.DatabaseExclude),
+		Webserver: overrideSetting(settings.Webserver, o.Webserver, o.WebserverExclude),
+		Files:     overrideSetting(settings.Files, o.Files, o.FilesExclude),
+		Misc:      overrideSetting(settings.Misc, o.Misc, o.MiscExclude),
+		Debug:     overrideSetting(settings.Debug, o.Debug, o.DebugExclude),
+	}
+}
+
+// ApplyGravity returns a copy of settings with the override applied.
+func (o *ReplicaOverride) ApplyGravity(settings *GravitySettings) *GravitySettings {
+	if o == nil || settings == nil {
+		return settings
+	}
+
+	return &GravitySettings{
+		DHCPLeases:        overrideBool(settings.DHCPLeases, o.DHCPLeases),
+		Group:             overrideBool(settings.Group, o.Group),
+		Adlist:            overrideBool(settings.Adlist, o.Adlist),
+		AdlistByGroup:     overrideBool(settings.AdlistByGroup, o.AdlistByGroup),
+		Domainlist:        overrideBool(settings.Domainlist, o.Domainlist),
+		DomainlistByGroup: overrideBool(settings.DomainlistByGroup, o.DomainlistByGroup),
+		Client:            overrideBool(settings.Client, o.Client),
+		ClientByGroup:     overrideBool(settings.ClientByGroup, o.ClientByGroup),
+	}
+}
+
+func overrideSetting(setting *ConfigSetting, enabled *bool, exclude []string) *ConfigSetting {
+	if setting == nil {
+		return nil
+	}
+
+	return &ConfigSetting{
+		Enabled: overrideBool(setting.Enabled, enabled),
+		Filter:  setting.Filter,
+		Exclude: append(slices.Clone(setting.Exclude), exclude...),
+	}
+}
+
+func overrideBool(value bool, override *bool) bool {
+	if override != nil {
+		return *override
+	}
+	return value
+}
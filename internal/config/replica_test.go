@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/sync/filter"
+)
+
+func TestConfig_Load_replicaOverrides(t *testing.T) {
+	conf := Config{}
+
+	t.Setenv("PRIMARY", "http://localhost:1337|asdf")
+	t.Setenv("REPLICAS", "http://localhost:1338|qwerty,http://localhost:1339|foobar")
+	t.Setenv("FULL_SYNC", "false")
+	t.Setenv("REPLICA_2_SYNC_CONFIG_DHCP", "false")
+	t.Setenv("REPLICA_2_SYNC_CONFIG_DNS_EXCLUDE", "interface")
+	t.Setenv("REPLICA_2_SYNC_GRAVITY_DHCP_LEASES", "false")
+
+	require.NoError(t, conf.Load())
+
+	require.Len(t, conf.Sync.ReplicaOverrides, 2)
+	assert.Equal(t, &ReplicaOverride{}, conf.Sync.ReplicaOverrides[0])
+
+	override := conf.Sync.ReplicaOverrides[1]
+	require.NotNil(t, override.DHCP)
+	assert.False(t, *override.DHCP)
+	assert.Equal(t, []string{"interface"}, override.DNSExclude)
+	require.NotNil(t, override.DHCPLeases)
+	assert.False(t, *override.DHCPLeases)
+	assert.Nil(t, override.DNS)
+}
+
+func TestReplicaOverride_ApplyConfig(t *testing.T) {
+	disabled := false
+	override := &ReplicaOverride{
+		DHCP:       &disabled,
+		DNSExclude: []string{"interface"},
+	}
+
+	settings := &ConfigSettings{
+		DNS:  NewConfigSetting(true, []string{"upstreams", "interface"}, nil),
+		DHCP: NewConfigSetting(true, nil, nil),
+		NTP:  NewConfigSetting(true, nil, nil),
+	}
+
+	applied := override.ApplyConfig(settings)
+
+	assert.True(t, applied.DNS.Enabled)
+	assert.Equal(t, filter.Include, applied.DNS.Filter.Type)
+	assert.Equal(t, []string{"interface"}, applied.DNS.Exclude)
+	assert.False(t, applied.DHCP.Enabled)
+	assert.True(t, applied.NTP.Enabled)
+	assert.Nil(t, applied.Misc)
+	assert.True(t, settings.DHCP.Enabled, "shared settings must not change")
+}
+
+func TestReplicaOverride_ApplyGravity(t *testing.T) {
+	disabled := false
+	override := &ReplicaOverride{DHCPLeases: &disabled}
+
+	applied := override.ApplyGravity(&GravitySettings{DHCPLeases: true, Group: true})
+
+	assert.Equal(t, &GravitySettings{DHCPLeases: false, Group: true}, applied)
+}
+
+func TestReplicaOverride_nil(t *testing.T) {
+	var override *ReplicaOverride
+	settings := &ConfigSettings{}
+	gravity := &GravitySettings{}
+
+	assert.Same(t, settings, override.ApplyConfig(settings))
+	assert.Same(t, gravity, override.ApplyGravity(gravity))
+}
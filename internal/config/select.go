@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Select returns a copy of the settings where only the named sections, e.g. dns or dhcp, are enabled.
+// Filters of the selected sections are kept.
+func (cs *ConfigSettings) Select(sections []string) (*ConfigSettings, error) {
+	if cs == nil {
+		cs = &ConfigSettings{}
+	}
+
+	selected := &ConfigSettings{}
+	settings := map[string]struct {
+		from *ConfigSetting
+		to   **ConfigSetting
+	}{
+		"dns":       {cs.DNS, &selected.DNS},
+		"dhcp":      {cs.DHCP, &selected.DHCP},
+		"ntp":       {cs.NTP, &selected.NTP},
+		"resolver":  {cs.Resolver, &selected.Resolver},
+		"database":  {cs.Database, &selected.Database},
+		"webserver": {cs.Webserver, &selected.Webserver},
+		"files":     {cs.Files, &selected.Files},
+		"misc":      {cs.Misc, &selected.Misc},
+		"debug":     {cs.Debug, &selected.Debug},
+	}
+
+	for _, section := range sections {
+		if _, exists := settings[section]; !exists {
+			return nil, fmt.Errorf("unknown config section %q", section)
+		}
+	}
+
+	for section, setting := range settings {
+		copied := ConfigSetting{Enabled: slices.Contains(sections, section)}
+		if setting.from != nil {
+			copied.Filter = setting.from.Filter
+			copied.Exclude = setting.from.Exclude
+		}
+		*setting.to = &copied
+	}
+
+	return selected, nil
+}
+
+// SelectGravity returns gravity settings where only the named teleporter tables, e.g. adlist or group, are synced.
+func SelectGravity(tables []string) (*GravitySettings, error) {
+	selected := &GravitySettings{}
+	settings := map[string]*bool{
+		"dhcp_leases":         &selected.DHCPLeases,
+		"group":               &selected.Group,
+		"adlist":              &selected.Adlist,
+		"adlist_by_group":     &selected.AdlistByGroup,
+		"domainlist":          &selected.Domainlist,
+		"domainlist_by_group": &selected.DomainlistByGroup,
+		"client":              &selected.Client,
+		"client_by_group":     &selected.ClientByGroup,
+	}
+
+	for _, table := range tables {
+		setting, exists := settings[table]
+		if !exists {
+			return nil, fmt.Errorf("unknown gravity table %q", table)
+		}
+		*setting = true
+	}
+
+	return selected, nil
+}
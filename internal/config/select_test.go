@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSettings_Select(t *testing.T) {
+	settings := &ConfigSettings{
+		DNS:  NewConfigSetting(false, nil, []string{"interface"}),
+		DHCP: NewConfigSetting(true, nil, nil),
+	}
+
+	selected, err := settings.Select([]string{"dns", "misc"})
+	require.NoError(t, err)
+
+	assert.True(t, selected.DNS.Enabled)
+	assert.Equal(t, settings.DNS.Filter, selected.DNS.Filter)
+	assert.True(t, selected.Misc.Enabled)
+	assert.False(t, selected.DHCP.Enabled)
+	assert.False(t, selected.Debug.Enabled)
+	assert.False(t, settings.DNS.Enabled)
+
+	_, err = settings.Select([]string{"gravity"})
+	assert.ErrorContains(t, err, `unknown config section "gravity"`)
+}
+
+func TestSelectGravity(t *testing.T) {
+	selected, err := SelectGravity([]string{"adlist", "group"})
+	require.NoError(t, err)
+	assert.Equal(t, &GravitySettings{Adlist: true, Group: true}, selected)
+
+	_, err = SelectGravity([]string{"adlists"})
+	assert.ErrorContains(t, err, `unknown gravity table "adlists"`)
+}
@@ -6,26 +6,56 @@ import (
 	"strings"
 
 	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/pihole/totp"
 )
 
 func (c *Config) loadTargets() error {
-	primary, err := loadPrimary()
+	primary, err := loadPrimary(c.env("PRIMARY"))
 	if err != nil {
 		return err
 	}
 
-	replicas, err := loadReplicas()
+	replicas, err := loadReplicas(c.env("REPLICAS"))
 	if err != nil {
 		return err
 	}
 
+	if primary.TOTPSecret, err = loadTOTPSecret(c.env("PRIMARY_TOTP_SECRET")); err != nil {
+		return err
+	}
+	for i := range replicas {
+		if replicas[i].TOTPSecret, err = loadTOTPSecret(c.env(fmt.Sprintf("REPLICA_%d_TOTP_SECRET", i+1))); err != nil {
+			return err
+		}
+	}
+
 	c.Primary = *primary
 	c.Replicas = replicas
 	return nil
 }
 
-func loadPrimary() (*model.PiHole, error) {
-	env := "PRIMARY"
+// loadTOTPSecret reads the optional TOTP secret from env or from the file named by env suffixed with _FILE.
+func loadTOTPSecret(env string) (string, error) {
+	secret := os.Getenv(env)
+	if fileValue := os.Getenv(fmt.Sprintf("%s_FILE", env)); len(fileValue) > 0 {
+		bytes, err := os.ReadFile(fileValue)
+		if err != nil {
+			return "", err
+		}
+		secret = strings.TrimSpace(string(bytes))
+	}
+
+	if secret == "" {
+		return "", nil
+	}
+
+	if err := totp.Validate(secret); err != nil {
+		return "", fmt.Errorf("%s: %w", env, err)
+	}
+	return secret, nil
+}
+
+func loadPrimary(env string) (*model.PiHole, error) {
 	if fileValue := os.Getenv(fmt.Sprintf("%s_FILE", env)); len(fileValue) > 0 {
 		bytes, err := os.ReadFile(fileValue)
 		if err != nil {
@@ -40,8 +70,7 @@ func loadPrimary() (*model.PiHole, error) {
 	return nil, fmt.Errorf("missing required env: %s/%s_FILE", env, env)
 }
 
-func loadReplicas() ([]model.PiHole, error) {
-	env := "REPLICAS"
+func loadReplicas(env string) ([]model.PiHole, error) {
 	if fileValue := os.Getenv(fmt.Sprintf("%s_FILE", env)); len(fileValue) > 0 {
 		bytes, err := os.ReadFile(fileValue)
 		if err != nil {
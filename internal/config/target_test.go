@@ -71,3 +71,28 @@ func TestConfig_Load_NoReplicas(t *testing.T) {
 	err := conf.loadTargets()
 	assert.Error(t, err)
 }
+
+func TestConfig_Load_TargetTOTPSecrets(t *testing.T) {
+	conf := Config{}
+
+	t.Setenv("PRIMARY", "http://localhost:1337|asdf")
+	t.Setenv("REPLICAS", "http://localhost:1338|qwerty,http://localhost:1339|foobar")
+	t.Setenv("PRIMARY_TOTP_SECRET", "gezd gnbv gy3t qojq")
+	t.Setenv("REPLICA_2_TOTP_SECRET_FILE", "../../testdata/totp_secret")
+
+	require.NoError(t, conf.loadTargets())
+
+	assert.Equal(t, "gezd gnbv gy3t qojq", conf.Primary.TOTPSecret)
+	assert.Empty(t, conf.Replicas[0].TOTPSecret)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", conf.Replicas[1].TOTPSecret)
+}
+
+func TestConfig_Load_TargetInvalidTOTPSecret(t *testing.T) {
+	conf := Config{}
+
+	t.Setenv("PRIMARY", "http://localhost:1337|asdf")
+	t.Setenv("REPLICAS", "http://localhost:1338|qwerty")
+	t.Setenv("REPLICA_1_TOTP_SECRET", "not base32!")
+
+	assert.ErrorContains(t, conf.loadTargets(), "REPLICA_1_TOTP_SECRET: decode totp secret")
+}
@@ -8,7 +8,9 @@ import (
 
 type WebhookSettings struct {
 	Failure WebhookRequest `ignored:"true"`
+	Partial WebhookRequest `ignored:"true"`
 	Success WebhookRequest `ignored:"true"`
+	Drift   WebhookRequest `ignored:"true"`
 	Client  WebhookClient  `ignored:"true"`
 }
 
@@ -26,13 +28,19 @@ type WebhookRequest struct {
 func (c *Config) loadWebhookSettings() error {
 	webhookSettings := WebhookSettings{}
 
-	if err := envconfig.Process("WEBHOOK_SYNC_FAILURE", &webhookSettings.Failure); err != nil {
+	if err := envconfig.Process(c.env("WEBHOOK_SYNC_FAILURE"), &webhookSettings.Failure); err != nil {
 		return fmt.Errorf("process webhook env vars for failure: %w", err)
 	}
-	if err := envconfig.Process("WEBHOOK_SYNC_SUCCESS", &webhookSettings.Success); err != nil {
+	if err := envconfig.Process(c.env("WEBHOOK_SYNC_PARTIAL"), &webhookSettings.Partial); err != nil {
+		return fmt.Errorf("process webhook env vars for partial: %w", err)
+	}
+	if err := envconfig.Process(c.env("WEBHOOK_SYNC_SUCCESS"), &webhookSettings.Success); err != nil {
 		return fmt.Errorf("process webhook env vars for success: %w", err)
 	}
-	if err := envconfig.Process("WEBHOOK_CLIENT", &webhookSettings.Client); err != nil {
+	if err := envconfig.Process(c.env("WEBHOOK_SYNC_DRIFT"), &webhookSettings.Drift); err != nil {
+		return fmt.Errorf("process webhook env vars for drift: %w", err)
+	}
+	if err := envconfig.Process(c.env("WEBHOOK_CLIENT"), &webhookSettings.Client); err != nil {
 		return fmt.Errorf("process webhook env vars for client: %w", err)
 	}
 
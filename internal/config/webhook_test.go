@@ -109,3 +109,19 @@ func TestWebhookSettings_ClientConfiguration(t *testing.T) {
 	require.NotNil(t, conf.Sync.WebhookSettings.Client)
 	assert.True(t, conf.Sync.WebhookSettings.Client.SkipTLSVerification)
 }
+
+func TestWebhookSettings_Load_Partial(t *testing.T) {
+	t.Setenv("WEBHOOK_SYNC_PARTIAL_URL", "http://partial.example.com")
+	t.Setenv("WEBHOOK_SYNC_PARTIAL_BODY", "{\"status\":\"partial\"}")
+
+	conf := Config{
+		Sync: &Sync{},
+	}
+	err := conf.loadWebhookSettings()
+	require.NoError(t, err)
+
+	partial := conf.Sync.WebhookSettings.Partial
+	assert.Equal(t, "http://partial.example.com", partial.URL)
+	assert.Equal(t, "POST", partial.Method)
+	assert.JSONEq(t, `{"status":"partial"}`, partial.Body)
+}
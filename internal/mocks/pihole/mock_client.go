@@ -5,6 +5,7 @@
 package pihole
 
 import (
+	"context"
 	"github.com/lovelaze/nebula-sync/internal/pihole/model"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -81,260 +82,1149 @@ func (_c *Client_APIPath_Call) RunAndReturn(run func(target string) string) *Cli
 	return _c
 }
 
+// DeleteClient provides a mock function for the type Client
+func (_mock *Client) DeleteClient(ctx context.Context, client string) error {
+	ret := _mock.Called(ctx, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteClient")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, client)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_DeleteClient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteClient'
+type Client_DeleteClient_Call struct {
+	*mock.Call
+}
+
+// DeleteClient is a helper method to define mock.On call
+//   - ctx
+//   - client
+func (_e *Client_Expecter) DeleteClient(ctx interface{}, client interface{}) *Client_DeleteClient_Call {
+	return &Client_DeleteClient_Call{Call: _e.mock.On("DeleteClient", ctx, client)}
+}
+
+func (_c *Client_DeleteClient_Call) Run(run func(ctx context.Context, client string)) *Client_DeleteClient_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteClient_Call) Return(err error) *Client_DeleteClient_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_DeleteClient_Call) RunAndReturn(run func(ctx context.Context, client string) error) *Client_DeleteClient_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteClients provides a mock function for the type Client
+func (_mock *Client) DeleteClients(ctx context.Context, clients []string) error {
+	ret := _mock.Called(ctx, clients)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteClients")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = returnFunc(ctx, clients)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_DeleteClients_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteClients'
+type Client_DeleteClients_Call struct {
+	*mock.Call
+}
+
+// DeleteClients is a helper method to define mock.On call
+//   - ctx
+//   - clients
+func (_e *Client_Expecter) DeleteClients(ctx interface{}, clients interface{}) *Client_DeleteClients_Call {
+	return &Client_DeleteClients_Call{Call: _e.mock.On("DeleteClients", ctx, clients)}
+}
+
+func (_c *Client_DeleteClients_Call) Run(run func(ctx context.Context, clients []string)) *Client_DeleteClients_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteClients_Call) Return(err error) *Client_DeleteClients_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_DeleteClients_Call) RunAndReturn(run func(ctx context.Context, clients []string) error) *Client_DeleteClients_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteDomain provides a mock function for the type Client
+func (_mock *Client) DeleteDomain(ctx context.Context, domain string, domainType string, kind string) error {
+	ret := _mock.Called(ctx, domain, domainType, kind)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDomain")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = returnFunc(ctx, domain, domainType, kind)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_DeleteDomain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDomain'
+type Client_DeleteDomain_Call struct {
+	*mock.Call
+}
+
+// DeleteDomain is a helper method to define mock.On call
+//   - ctx
+//   - domain
+//   - domainType
+//   - kind
+func (_e *Client_Expecter) DeleteDomain(ctx interface{}, domain interface{}, domainType interface{}, kind interface{}) *Client_DeleteDomain_Call {
+	return &Client_DeleteDomain_Call{Call: _e.mock.On("DeleteDomain", ctx, domain, domainType, kind)}
+}
+
+func (_c *Client_DeleteDomain_Call) Run(run func(ctx context.Context, domain string, domainType string, kind string)) *Client_DeleteDomain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteDomain_Call) Return(err error) *Client_DeleteDomain_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_DeleteDomain_Call) RunAndReturn(run func(ctx context.Context, domain string, domainType string, kind string) error) *Client_DeleteDomain_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteDomains provides a mock function for the type Client
+func (_mock *Client) DeleteDomains(ctx context.Context, items []model.BatchDeleteItem) error {
+	ret := _mock.Called(ctx, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDomains")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []model.BatchDeleteItem) error); ok {
+		r0 = returnFunc(ctx, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_DeleteDomains_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDomains'
+type Client_DeleteDomains_Call struct {
+	*mock.Call
+}
+
+// DeleteDomains is a helper method to define mock.On call
+//   - ctx
+//   - items
+func (_e *Client_Expecter) DeleteDomains(ctx interface{}, items interface{}) *Client_DeleteDomains_Call {
+	return &Client_DeleteDomains_Call{Call: _e.mock.On("DeleteDomains", ctx, items)}
+}
+
+func (_c *Client_DeleteDomains_Call) Run(run func(ctx context.Context, items []model.BatchDeleteItem)) *Client_DeleteDomains_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]model.BatchDeleteItem))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteDomains_Call) Return(err error) *Client_DeleteDomains_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_DeleteDomains_Call) RunAndReturn(run func(ctx context.Context, items []model.BatchDeleteItem) error) *Client_DeleteDomains_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteGroup provides a mock function for the type Client
+func (_mock *Client) DeleteGroup(ctx context.Context, name string) error {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteGroup")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_DeleteGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteGroup'
+type Client_DeleteGroup_Call struct {
+	*mock.Call
+}
+
+// DeleteGroup is a helper method to define mock.On call
+//   - ctx
+//   - name
+func (_e *Client_Expecter) DeleteGroup(ctx interface{}, name interface{}) *Client_DeleteGroup_Call {
+	return &Client_DeleteGroup_Call{Call: _e.mock.On("DeleteGroup", ctx, name)}
+}
+
+func (_c *Client_DeleteGroup_Call) Run(run func(ctx context.Context, name string)) *Client_DeleteGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteGroup_Call) Return(err error) *Client_DeleteGroup_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_DeleteGroup_Call) RunAndReturn(run func(ctx context.Context, name string) error) *Client_DeleteGroup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteGroups provides a mock function for the type Client
+func (_mock *Client) DeleteGroups(ctx context.Context, names []string) error {
+	ret := _mock.Called(ctx, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteGroups")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = returnFunc(ctx, names)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_DeleteGroups_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteGroups'
+type Client_DeleteGroups_Call struct {
+	*mock.Call
+}
+
+// DeleteGroups is a helper method to define mock.On call
+//   - ctx
+//   - names
+func (_e *Client_Expecter) DeleteGroups(ctx interface{}, names interface{}) *Client_DeleteGroups_Call {
+	return &Client_DeleteGroups_Call{Call: _e.mock.On("DeleteGroups", ctx, names)}
+}
+
+func (_c *Client_DeleteGroups_Call) Run(run func(ctx context.Context, names []string)) *Client_DeleteGroups_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteGroups_Call) Return(err error) *Client_DeleteGroups_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_DeleteGroups_Call) RunAndReturn(run func(ctx context.Context, names []string) error) *Client_DeleteGroups_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteList provides a mock function for the type Client
+func (_mock *Client) DeleteList(ctx context.Context, address string, listType string) error {
+	ret := _mock.Called(ctx, address, listType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteList")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, address, listType)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_DeleteList_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteList'
+type Client_DeleteList_Call struct {
+	*mock.Call
+}
+
+// DeleteList is a helper method to define mock.On call
+//   - ctx
+//   - address
+//   - listType
+func (_e *Client_Expecter) DeleteList(ctx interface{}, address interface{}, listType interface{}) *Client_DeleteList_Call {
+	return &Client_DeleteList_Call{Call: _e.mock.On("DeleteList", ctx, address, listType)}
+}
+
+func (_c *Client_DeleteList_Call) Run(run func(ctx context.Context, address string, listType string)) *Client_DeleteList_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteList_Call) Return(err error) *Client_DeleteList_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_DeleteList_Call) RunAndReturn(run func(ctx context.Context, address string, listType string) error) *Client_DeleteList_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteLists provides a mock function for the type Client
+func (_mock *Client) DeleteLists(ctx context.Context, items []model.BatchDeleteItem) error {
+	ret := _mock.Called(ctx, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLists")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []model.BatchDeleteItem) error); ok {
+		r0 = returnFunc(ctx, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_DeleteLists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteLists'
+type Client_DeleteLists_Call struct {
+	*mock.Call
+}
+
+// DeleteLists is a helper method to define mock.On call
+//   - ctx
+//   - items
+func (_e *Client_Expecter) DeleteLists(ctx interface{}, items interface{}) *Client_DeleteLists_Call {
+	return &Client_DeleteLists_Call{Call: _e.mock.On("DeleteLists", ctx, items)}
+}
+
+func (_c *Client_DeleteLists_Call) Run(run func(ctx context.Context, items []model.BatchDeleteItem)) *Client_DeleteLists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]model.BatchDeleteItem))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteLists_Call) Return(err error) *Client_DeleteLists_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_DeleteLists_Call) RunAndReturn(run func(ctx context.Context, items []model.BatchDeleteItem) error) *Client_DeleteLists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteSession provides a mock function for the type Client
-func (_mock *Client) DeleteSession() error {
+func (_mock *Client) DeleteSession(ctx context.Context) error {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSession")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_DeleteSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSession'
+type Client_DeleteSession_Call struct {
+	*mock.Call
+}
+
+// DeleteSession is a helper method to define mock.On call
+//   - ctx
+func (_e *Client_Expecter) DeleteSession(ctx interface{}) *Client_DeleteSession_Call {
+	return &Client_DeleteSession_Call{Call: _e.mock.On("DeleteSession", ctx)}
+}
+
+func (_c *Client_DeleteSession_Call) Run(run func(ctx context.Context)) *Client_DeleteSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteSession_Call) Return(err error) *Client_DeleteSession_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_DeleteSession_Call) RunAndReturn(run func(ctx context.Context) error) *Client_DeleteSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetClients provides a mock function for the type Client
+func (_mock *Client) GetClients(ctx context.Context) ([]model.Client, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetClients")
+	}
+
+	var r0 []model.Client
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]model.Client, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []model.Client); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Client)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Client_GetClients_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetClients'
+type Client_GetClients_Call struct {
+	*mock.Call
+}
+
+// GetClients is a helper method to define mock.On call
+//   - ctx
+func (_e *Client_Expecter) GetClients(ctx interface{}) *Client_GetClients_Call {
+	return &Client_GetClients_Call{Call: _e.mock.On("GetClients", ctx)}
+}
+
+func (_c *Client_GetClients_Call) Run(run func(ctx context.Context)) *Client_GetClients_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_GetClients_Call) Return(clients []model.Client, err error) *Client_GetClients_Call {
+	_c.Call.Return(clients, err)
+	return _c
+}
+
+func (_c *Client_GetClients_Call) RunAndReturn(run func(ctx context.Context) ([]model.Client, error)) *Client_GetClients_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetConfig provides a mock function for the type Client
+func (_mock *Client) GetConfig(ctx context.Context) (*model.ConfigResponse, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetConfig")
+	}
+
+	var r0 *model.ConfigResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*model.ConfigResponse, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *model.ConfigResponse); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ConfigResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Client_GetConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetConfig'
+type Client_GetConfig_Call struct {
+	*mock.Call
+}
+
+// GetConfig is a helper method to define mock.On call
+//   - ctx
+func (_e *Client_Expecter) GetConfig(ctx interface{}) *Client_GetConfig_Call {
+	return &Client_GetConfig_Call{Call: _e.mock.On("GetConfig", ctx)}
+}
+
+func (_c *Client_GetConfig_Call) Run(run func(ctx context.Context)) *Client_GetConfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_GetConfig_Call) Return(configResponse *model.ConfigResponse, err error) *Client_GetConfig_Call {
+	_c.Call.Return(configResponse, err)
+	return _c
+}
+
+func (_c *Client_GetConfig_Call) RunAndReturn(run func(ctx context.Context) (*model.ConfigResponse, error)) *Client_GetConfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDomains provides a mock function for the type Client
+func (_mock *Client) GetDomains(ctx context.Context, domainType string, kind string) ([]model.Domain, error) {
+	ret := _mock.Called(ctx, domainType, kind)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDomains")
+	}
+
+	var r0 []model.Domain
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) ([]model.Domain, error)); ok {
+		return returnFunc(ctx, domainType, kind)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) []model.Domain); ok {
+		r0 = returnFunc(ctx, domainType, kind)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Domain)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, domainType, kind)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Client_GetDomains_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDomains'
+type Client_GetDomains_Call struct {
+	*mock.Call
+}
+
+// GetDomains is a helper method to define mock.On call
+//   - ctx
+//   - domainType
+//   - kind
+func (_e *Client_Expecter) GetDomains(ctx interface{}, domainType interface{}, kind interface{}) *Client_GetDomains_Call {
+	return &Client_GetDomains_Call{Call: _e.mock.On("GetDomains", ctx, domainType, kind)}
+}
+
+func (_c *Client_GetDomains_Call) Run(run func(ctx context.Context, domainType string, kind string)) *Client_GetDomains_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Client_GetDomains_Call) Return(domains []model.Domain, err error) *Client_GetDomains_Call {
+	_c.Call.Return(domains, err)
+	return _c
+}
+
+func (_c *Client_GetDomains_Call) RunAndReturn(run func(ctx context.Context, domainType string, kind string) ([]model.Domain, error)) *Client_GetDomains_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetGroups provides a mock function for the type Client
+func (_mock *Client) GetGroups(ctx context.Context) ([]model.Group, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGroups")
+	}
+
+	var r0 []model.Group
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]model.Group, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []model.Group); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Group)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Client_GetGroups_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGroups'
+type Client_GetGroups_Call struct {
+	*mock.Call
+}
+
+// GetGroups is a helper method to define mock.On call
+//   - ctx
+func (_e *Client_Expecter) GetGroups(ctx interface{}) *Client_GetGroups_Call {
+	return &Client_GetGroups_Call{Call: _e.mock.On("GetGroups", ctx)}
+}
+
+func (_c *Client_GetGroups_Call) Run(run func(ctx context.Context)) *Client_GetGroups_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_GetGroups_Call) Return(groups []model.Group, err error) *Client_GetGroups_Call {
+	_c.Call.Return(groups, err)
+	return _c
+}
+
+func (_c *Client_GetGroups_Call) RunAndReturn(run func(ctx context.Context) ([]model.Group, error)) *Client_GetGroups_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLists provides a mock function for the type Client
+func (_mock *Client) GetLists(ctx context.Context) ([]model.List, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLists")
+	}
+
+	var r0 []model.List
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]model.List, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []model.List); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.List)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Client_GetLists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLists'
+type Client_GetLists_Call struct {
+	*mock.Call
+}
+
+// GetLists is a helper method to define mock.On call
+//   - ctx
+func (_e *Client_Expecter) GetLists(ctx interface{}) *Client_GetLists_Call {
+	return &Client_GetLists_Call{Call: _e.mock.On("GetLists", ctx)}
+}
+
+func (_c *Client_GetLists_Call) Run(run func(ctx context.Context)) *Client_GetLists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_GetLists_Call) Return(lists []model.List, err error) *Client_GetLists_Call {
+	_c.Call.Return(lists, err)
+	return _c
+}
+
+func (_c *Client_GetLists_Call) RunAndReturn(run func(ctx context.Context) ([]model.List, error)) *Client_GetLists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTeleporter provides a mock function for the type Client
+func (_mock *Client) GetTeleporter(ctx context.Context) ([]byte, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeleporter")
+	}
+
+	var r0 []byte
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]byte, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []byte); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Client_GetTeleporter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTeleporter'
+type Client_GetTeleporter_Call struct {
+	*mock.Call
+}
+
+// GetTeleporter is a helper method to define mock.On call
+//   - ctx
+func (_e *Client_Expecter) GetTeleporter(ctx interface{}) *Client_GetTeleporter_Call {
+	return &Client_GetTeleporter_Call{Call: _e.mock.On("GetTeleporter", ctx)}
+}
+
+func (_c *Client_GetTeleporter_Call) Run(run func(ctx context.Context)) *Client_GetTeleporter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_GetTeleporter_Call) Return(bytes []byte, err error) *Client_GetTeleporter_Call {
+	_c.Call.Return(bytes, err)
+	return _c
+}
+
+func (_c *Client_GetTeleporter_Call) RunAndReturn(run func(ctx context.Context) ([]byte, error)) *Client_GetTeleporter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetVersion provides a mock function for the type Client
+func (_mock *Client) GetVersion(ctx context.Context) (*model.VersionResponse, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVersion")
+	}
+
+	var r0 *model.VersionResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*model.VersionResponse, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *model.VersionResponse); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.VersionResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Client_GetVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVersion'
+type Client_GetVersion_Call struct {
+	*mock.Call
+}
+
+// GetVersion is a helper method to define mock.On call
+//   - ctx
+func (_e *Client_Expecter) GetVersion(ctx interface{}) *Client_GetVersion_Call {
+	return &Client_GetVersion_Call{Call: _e.mock.On("GetVersion", ctx)}
+}
+
+func (_c *Client_GetVersion_Call) Run(run func(ctx context.Context)) *Client_GetVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_GetVersion_Call) Return(versionResponse *model.VersionResponse, err error) *Client_GetVersion_Call {
+	_c.Call.Return(versionResponse, err)
+	return _c
+}
+
+func (_c *Client_GetVersion_Call) RunAndReturn(run func(ctx context.Context) (*model.VersionResponse, error)) *Client_GetVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HasSession provides a mock function for the type Client
+func (_mock *Client) HasSession() bool {
 	ret := _mock.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteSession")
+		panic("no return value specified for HasSession")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func() bool); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// Client_HasSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HasSession'
+type Client_HasSession_Call struct {
+	*mock.Call
+}
+
+// HasSession is a helper method to define mock.On call
+func (_e *Client_Expecter) HasSession() *Client_HasSession_Call {
+	return &Client_HasSession_Call{Call: _e.mock.On("HasSession")}
+}
+
+func (_c *Client_HasSession_Call) Run(run func()) *Client_HasSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_HasSession_Call) Return(b bool) *Client_HasSession_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *Client_HasSession_Call) RunAndReturn(run func() bool) *Client_HasSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PatchConfig provides a mock function for the type Client
+func (_mock *Client) PatchConfig(ctx context.Context, patchRequest *model.PatchConfigRequest) error {
+	ret := _mock.Called(ctx, patchRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchConfig")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func() error); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *model.PatchConfigRequest) error); ok {
+		r0 = returnFunc(ctx, patchRequest)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// Client_DeleteSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSession'
-type Client_DeleteSession_Call struct {
+// Client_PatchConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PatchConfig'
+type Client_PatchConfig_Call struct {
 	*mock.Call
 }
 
-// DeleteSession is a helper method to define mock.On call
-func (_e *Client_Expecter) DeleteSession() *Client_DeleteSession_Call {
-	return &Client_DeleteSession_Call{Call: _e.mock.On("DeleteSession")}
+// PatchConfig is a helper method to define mock.On call
+//   - ctx
+//   - patchRequest
+func (_e *Client_Expecter) PatchConfig(ctx interface{}, patchRequest interface{}) *Client_PatchConfig_Call {
+	return &Client_PatchConfig_Call{Call: _e.mock.On("PatchConfig", ctx, patchRequest)}
 }
 
-func (_c *Client_DeleteSession_Call) Run(run func()) *Client_DeleteSession_Call {
+func (_c *Client_PatchConfig_Call) Run(run func(ctx context.Context, patchRequest *model.PatchConfigRequest)) *Client_PatchConfig_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context), args[1].(*model.PatchConfigRequest))
 	})
 	return _c
 }
 
-func (_c *Client_DeleteSession_Call) Return(err error) *Client_DeleteSession_Call {
+func (_c *Client_PatchConfig_Call) Return(err error) *Client_PatchConfig_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *Client_DeleteSession_Call) RunAndReturn(run func() error) *Client_DeleteSession_Call {
+func (_c *Client_PatchConfig_Call) RunAndReturn(run func(ctx context.Context, patchRequest *model.PatchConfigRequest) error) *Client_PatchConfig_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetConfig provides a mock function for the type Client
-func (_mock *Client) GetConfig() (*model.ConfigResponse, error) {
-	ret := _mock.Called()
+// PostAuth provides a mock function for the type Client
+func (_mock *Client) PostAuth(ctx context.Context) error {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetConfig")
+		panic("no return value specified for PostAuth")
 	}
 
-	var r0 *model.ConfigResponse
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() (*model.ConfigResponse, error)); ok {
-		return returnFunc()
-	}
-	if returnFunc, ok := ret.Get(0).(func() *model.ConfigResponse); ok {
-		r0 = returnFunc()
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.ConfigResponse)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// Client_GetConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetConfig'
-type Client_GetConfig_Call struct {
+// Client_PostAuth_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PostAuth'
+type Client_PostAuth_Call struct {
 	*mock.Call
 }
 
-// GetConfig is a helper method to define mock.On call
-func (_e *Client_Expecter) GetConfig() *Client_GetConfig_Call {
-	return &Client_GetConfig_Call{Call: _e.mock.On("GetConfig")}
+// PostAuth is a helper method to define mock.On call
+//   - ctx
+func (_e *Client_Expecter) PostAuth(ctx interface{}) *Client_PostAuth_Call {
+	return &Client_PostAuth_Call{Call: _e.mock.On("PostAuth", ctx)}
 }
 
-func (_c *Client_GetConfig_Call) Run(run func()) *Client_GetConfig_Call {
+func (_c *Client_PostAuth_Call) Run(run func(ctx context.Context)) *Client_PostAuth_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context))
 	})
 	return _c
 }
 
-func (_c *Client_GetConfig_Call) Return(configResponse *model.ConfigResponse, err error) *Client_GetConfig_Call {
-	_c.Call.Return(configResponse, err)
+func (_c *Client_PostAuth_Call) Return(err error) *Client_PostAuth_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *Client_GetConfig_Call) RunAndReturn(run func() (*model.ConfigResponse, error)) *Client_GetConfig_Call {
+func (_c *Client_PostAuth_Call) RunAndReturn(run func(ctx context.Context) error) *Client_PostAuth_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTeleporter provides a mock function for the type Client
-func (_mock *Client) GetTeleporter() ([]byte, error) {
-	ret := _mock.Called()
+// PostClient provides a mock function for the type Client
+func (_mock *Client) PostClient(ctx context.Context, request *model.ClientRequest) error {
+	ret := _mock.Called(ctx, request)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTeleporter")
+		panic("no return value specified for PostClient")
 	}
 
-	var r0 []byte
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() ([]byte, error)); ok {
-		return returnFunc()
-	}
-	if returnFunc, ok := ret.Get(0).(func() []byte); ok {
-		r0 = returnFunc()
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *model.ClientRequest) error); ok {
+		r0 = returnFunc(ctx, request)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
-		}
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_PostClient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PostClient'
+type Client_PostClient_Call struct {
+	*mock.Call
+}
+
+// PostClient is a helper method to define mock.On call
+//   - ctx
+//   - request
+func (_e *Client_Expecter) PostClient(ctx interface{}, request interface{}) *Client_PostClient_Call {
+	return &Client_PostClient_Call{Call: _e.mock.On("PostClient", ctx, request)}
+}
+
+func (_c *Client_PostClient_Call) Run(run func(ctx context.Context, request *model.ClientRequest)) *Client_PostClient_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.ClientRequest))
+	})
+	return _c
+}
+
+func (_c *Client_PostClient_Call) Return(err error) *Client_PostClient_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_PostClient_Call) RunAndReturn(run func(ctx context.Context, request *model.ClientRequest) error) *Client_PostClient_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PostDomain provides a mock function for the type Client
+func (_mock *Client) PostDomain(ctx context.Context, domainType string, kind string, request *model.DomainRequest) error {
+	ret := _mock.Called(ctx, domainType, kind, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostDomain")
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *model.DomainRequest) error); ok {
+		r0 = returnFunc(ctx, domainType, kind, request)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// Client_GetTeleporter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTeleporter'
-type Client_GetTeleporter_Call struct {
+// Client_PostDomain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PostDomain'
+type Client_PostDomain_Call struct {
 	*mock.Call
 }
 
-// GetTeleporter is a helper method to define mock.On call
-func (_e *Client_Expecter) GetTeleporter() *Client_GetTeleporter_Call {
-	return &Client_GetTeleporter_Call{Call: _e.mock.On("GetTeleporter")}
+// PostDomain is a helper method to define mock.On call
+//   - ctx
+//   - domainType
+//   - kind
+//   - request
+func (_e *Client_Expecter) PostDomain(ctx interface{}, domainType interface{}, kind interface{}, request interface{}) *Client_PostDomain_Call {
+	return &Client_PostDomain_Call{Call: _e.mock.On("PostDomain", ctx, domainType, kind, request)}
 }
 
-func (_c *Client_GetTeleporter_Call) Run(run func()) *Client_GetTeleporter_Call {
+func (_c *Client_PostDomain_Call) Run(run func(ctx context.Context, domainType string, kind string, request *model.DomainRequest)) *Client_PostDomain_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*model.DomainRequest))
 	})
 	return _c
 }
 
-func (_c *Client_GetTeleporter_Call) Return(bytes []byte, err error) *Client_GetTeleporter_Call {
-	_c.Call.Return(bytes, err)
+func (_c *Client_PostDomain_Call) Return(err error) *Client_PostDomain_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *Client_GetTeleporter_Call) RunAndReturn(run func() ([]byte, error)) *Client_GetTeleporter_Call {
+func (_c *Client_PostDomain_Call) RunAndReturn(run func(ctx context.Context, domainType string, kind string, request *model.DomainRequest) error) *Client_PostDomain_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// PatchConfig provides a mock function for the type Client
-func (_mock *Client) PatchConfig(patchRequest *model.PatchConfigRequest) error {
-	ret := _mock.Called(patchRequest)
+// PostGroup provides a mock function for the type Client
+func (_mock *Client) PostGroup(ctx context.Context, request *model.GroupRequest) error {
+	ret := _mock.Called(ctx, request)
 
 	if len(ret) == 0 {
-		panic("no return value specified for PatchConfig")
+		panic("no return value specified for PostGroup")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*model.PatchConfigRequest) error); ok {
-		r0 = returnFunc(patchRequest)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *model.GroupRequest) error); ok {
+		r0 = returnFunc(ctx, request)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// Client_PatchConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PatchConfig'
-type Client_PatchConfig_Call struct {
+// Client_PostGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PostGroup'
+type Client_PostGroup_Call struct {
 	*mock.Call
 }
 
-// PatchConfig is a helper method to define mock.On call
-//   - patchRequest
-func (_e *Client_Expecter) PatchConfig(patchRequest interface{}) *Client_PatchConfig_Call {
-	return &Client_PatchConfig_Call{Call: _e.mock.On("PatchConfig", patchRequest)}
+// PostGroup is a helper method to define mock.On call
+//   - ctx
+//   - request
+func (_e *Client_Expecter) PostGroup(ctx interface{}, request interface{}) *Client_PostGroup_Call {
+	return &Client_PostGroup_Call{Call: _e.mock.On("PostGroup", ctx, request)}
 }
 
-func (_c *Client_PatchConfig_Call) Run(run func(patchRequest *model.PatchConfigRequest)) *Client_PatchConfig_Call {
+func (_c *Client_PostGroup_Call) Run(run func(ctx context.Context, request *model.GroupRequest)) *Client_PostGroup_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*model.PatchConfigRequest))
+		run(args[0].(context.Context), args[1].(*model.GroupRequest))
 	})
 	return _c
 }
 
-func (_c *Client_PatchConfig_Call) Return(err error) *Client_PatchConfig_Call {
+func (_c *Client_PostGroup_Call) Return(err error) *Client_PostGroup_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *Client_PatchConfig_Call) RunAndReturn(run func(patchRequest *model.PatchConfigRequest) error) *Client_PatchConfig_Call {
+func (_c *Client_PostGroup_Call) RunAndReturn(run func(ctx context.Context, request *model.GroupRequest) error) *Client_PostGroup_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// PostAuth provides a mock function for the type Client
-func (_mock *Client) PostAuth() error {
-	ret := _mock.Called()
+// PostList provides a mock function for the type Client
+func (_mock *Client) PostList(ctx context.Context, listType string, request *model.ListRequest) error {
+	ret := _mock.Called(ctx, listType, request)
 
 	if len(ret) == 0 {
-		panic("no return value specified for PostAuth")
+		panic("no return value specified for PostList")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func() error); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *model.ListRequest) error); ok {
+		r0 = returnFunc(ctx, listType, request)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// Client_PostAuth_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PostAuth'
-type Client_PostAuth_Call struct {
+// Client_PostList_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PostList'
+type Client_PostList_Call struct {
 	*mock.Call
 }
 
-// PostAuth is a helper method to define mock.On call
-func (_e *Client_Expecter) PostAuth() *Client_PostAuth_Call {
-	return &Client_PostAuth_Call{Call: _e.mock.On("PostAuth")}
+// PostList is a helper method to define mock.On call
+//   - ctx
+//   - listType
+//   - request
+func (_e *Client_Expecter) PostList(ctx interface{}, listType interface{}, request interface{}) *Client_PostList_Call {
+	return &Client_PostList_Call{Call: _e.mock.On("PostList", ctx, listType, request)}
 }
 
-func (_c *Client_PostAuth_Call) Run(run func()) *Client_PostAuth_Call {
+func (_c *Client_PostList_Call) Run(run func(ctx context.Context, listType string, request *model.ListRequest)) *Client_PostList_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context), args[1].(string), args[2].(*model.ListRequest))
 	})
 	return _c
 }
 
-func (_c *Client_PostAuth_Call) Return(err error) *Client_PostAuth_Call {
+func (_c *Client_PostList_Call) Return(err error) *Client_PostList_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *Client_PostAuth_Call) RunAndReturn(run func() error) *Client_PostAuth_Call {
+func (_c *Client_PostList_Call) RunAndReturn(run func(ctx context.Context, listType string, request *model.ListRequest) error) *Client_PostList_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // PostRunGravity provides a mock function for the type Client
-func (_mock *Client) PostRunGravity() error {
-	ret := _mock.Called()
+func (_mock *Client) PostRunGravity(ctx context.Context) error {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PostRunGravity")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func() error); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -347,13 +1237,14 @@ type Client_PostRunGravity_Call struct {
 }
 
 // PostRunGravity is a helper method to define mock.On call
-func (_e *Client_Expecter) PostRunGravity() *Client_PostRunGravity_Call {
-	return &Client_PostRunGravity_Call{Call: _e.mock.On("PostRunGravity")}
+//   - ctx
+func (_e *Client_Expecter) PostRunGravity(ctx interface{}) *Client_PostRunGravity_Call {
+	return &Client_PostRunGravity_Call{Call: _e.mock.On("PostRunGravity", ctx)}
 }
 
-func (_c *Client_PostRunGravity_Call) Run(run func()) *Client_PostRunGravity_Call {
+func (_c *Client_PostRunGravity_Call) Run(run func(ctx context.Context)) *Client_PostRunGravity_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context))
 	})
 	return _c
 }
@@ -363,22 +1254,22 @@ func (_c *Client_PostRunGravity_Call) Return(err error) *Client_PostRunGravity_C
 	return _c
 }
 
-func (_c *Client_PostRunGravity_Call) RunAndReturn(run func() error) *Client_PostRunGravity_Call {
+func (_c *Client_PostRunGravity_Call) RunAndReturn(run func(ctx context.Context) error) *Client_PostRunGravity_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // PostTeleporter provides a mock function for the type Client
-func (_mock *Client) PostTeleporter(payload []byte, teleporterRequest *model.PostTeleporterRequest) error {
-	ret := _mock.Called(payload, teleporterRequest)
+func (_mock *Client) PostTeleporter(ctx context.Context, payload []byte, teleporterRequest *model.PostTeleporterRequest) error {
+	ret := _mock.Called(ctx, payload, teleporterRequest)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PostTeleporter")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func([]byte, *model.PostTeleporterRequest) error); ok {
-		r0 = returnFunc(payload, teleporterRequest)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []byte, *model.PostTeleporterRequest) error); ok {
+		r0 = returnFunc(ctx, payload, teleporterRequest)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -391,15 +1282,16 @@ type Client_PostTeleporter_Call struct {
 }
 
 // PostTeleporter is a helper method to define mock.On call
+//   - ctx
 //   - payload
 //   - teleporterRequest
-func (_e *Client_Expecter) PostTeleporter(payload interface{}, teleporterRequest interface{}) *Client_PostTeleporter_Call {
-	return &Client_PostTeleporter_Call{Call: _e.mock.On("PostTeleporter", payload, teleporterRequest)}
+func (_e *Client_Expecter) PostTeleporter(ctx interface{}, payload interface{}, teleporterRequest interface{}) *Client_PostTeleporter_Call {
+	return &Client_PostTeleporter_Call{Call: _e.mock.On("PostTeleporter", ctx, payload, teleporterRequest)}
 }
 
-func (_c *Client_PostTeleporter_Call) Run(run func(payload []byte, teleporterRequest *model.PostTeleporterRequest)) *Client_PostTeleporter_Call {
+func (_c *Client_PostTeleporter_Call) Run(run func(ctx context.Context, payload []byte, teleporterRequest *model.PostTeleporterRequest)) *Client_PostTeleporter_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].([]byte), args[1].(*model.PostTeleporterRequest))
+		run(args[0].(context.Context), args[1].([]byte), args[2].(*model.PostTeleporterRequest))
 	})
 	return _c
 }
@@ -409,7 +1301,243 @@ func (_c *Client_PostTeleporter_Call) Return(err error) *Client_PostTeleporter_C
 	return _c
 }
 
-func (_c *Client_PostTeleporter_Call) RunAndReturn(run func(payload []byte, teleporterRequest *model.PostTeleporterRequest) error) *Client_PostTeleporter_Call {
+func (_c *Client_PostTeleporter_Call) RunAndReturn(run func(ctx context.Context, payload []byte, teleporterRequest *model.PostTeleporterRequest) error) *Client_PostTeleporter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutClient provides a mock function for the type Client
+func (_mock *Client) PutClient(ctx context.Context, client string, request *model.ClientRequest) error {
+	ret := _mock.Called(ctx, client, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutClient")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *model.ClientRequest) error); ok {
+		r0 = returnFunc(ctx, client, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_PutClient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutClient'
+type Client_PutClient_Call struct {
+	*mock.Call
+}
+
+// PutClient is a helper method to define mock.On call
+//   - ctx
+//   - client
+//   - request
+func (_e *Client_Expecter) PutClient(ctx interface{}, client interface{}, request interface{}) *Client_PutClient_Call {
+	return &Client_PutClient_Call{Call: _e.mock.On("PutClient", ctx, client, request)}
+}
+
+func (_c *Client_PutClient_Call) Run(run func(ctx context.Context, client string, request *model.ClientRequest)) *Client_PutClient_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*model.ClientRequest))
+	})
+	return _c
+}
+
+func (_c *Client_PutClient_Call) Return(err error) *Client_PutClient_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_PutClient_Call) RunAndReturn(run func(ctx context.Context, client string, request *model.ClientRequest) error) *Client_PutClient_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutDomain provides a mock function for the type Client
+func (_mock *Client) PutDomain(ctx context.Context, domain string, domainType string, kind string, request *model.DomainRequest) error {
+	ret := _mock.Called(ctx, domain, domainType, kind, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutDomain")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, *model.DomainRequest) error); ok {
+		r0 = returnFunc(ctx, domain, domainType, kind, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_PutDomain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutDomain'
+type Client_PutDomain_Call struct {
+	*mock.Call
+}
+
+// PutDomain is a helper method to define mock.On call
+//   - ctx
+//   - domain
+//   - domainType
+//   - kind
+//   - request
+func (_e *Client_Expecter) PutDomain(ctx interface{}, domain interface{}, domainType interface{}, kind interface{}, request interface{}) *Client_PutDomain_Call {
+	return &Client_PutDomain_Call{Call: _e.mock.On("PutDomain", ctx, domain, domainType, kind, request)}
+}
+
+func (_c *Client_PutDomain_Call) Run(run func(ctx context.Context, domain string, domainType string, kind string, request *model.DomainRequest)) *Client_PutDomain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(*model.DomainRequest))
+	})
+	return _c
+}
+
+func (_c *Client_PutDomain_Call) Return(err error) *Client_PutDomain_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_PutDomain_Call) RunAndReturn(run func(ctx context.Context, domain string, domainType string, kind string, request *model.DomainRequest) error) *Client_PutDomain_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutGroup provides a mock function for the type Client
+func (_mock *Client) PutGroup(ctx context.Context, name string, request *model.GroupRequest) error {
+	ret := _mock.Called(ctx, name, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutGroup")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *model.GroupRequest) error); ok {
+		r0 = returnFunc(ctx, name, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_PutGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutGroup'
+type Client_PutGroup_Call struct {
+	*mock.Call
+}
+
+// PutGroup is a helper method to define mock.On call
+//   - ctx
+//   - name
+//   - request
+func (_e *Client_Expecter) PutGroup(ctx interface{}, name interface{}, request interface{}) *Client_PutGroup_Call {
+	return &Client_PutGroup_Call{Call: _e.mock.On("PutGroup", ctx, name, request)}
+}
+
+func (_c *Client_PutGroup_Call) Run(run func(ctx context.Context, name string, request *model.GroupRequest)) *Client_PutGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*model.GroupRequest))
+	})
+	return _c
+}
+
+func (_c *Client_PutGroup_Call) Return(err error) *Client_PutGroup_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_PutGroup_Call) RunAndReturn(run func(ctx context.Context, name string, request *model.GroupRequest) error) *Client_PutGroup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutList provides a mock function for the type Client
+func (_mock *Client) PutList(ctx context.Context, address string, listType string, request *model.ListRequest) error {
+	ret := _mock.Called(ctx, address, listType, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutList")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *model.ListRequest) error); ok {
+		r0 = returnFunc(ctx, address, listType, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_PutList_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutList'
+type Client_PutList_Call struct {
+	*mock.Call
+}
+
+// PutList is a helper method to define mock.On call
+//   - ctx
+//   - address
+//   - listType
+//   - request
+func (_e *Client_Expecter) PutList(ctx interface{}, address interface{}, listType interface{}, request interface{}) *Client_PutList_Call {
+	return &Client_PutList_Call{Call: _e.mock.On("PutList", ctx, address, listType, request)}
+}
+
+func (_c *Client_PutList_Call) Run(run func(ctx context.Context, address string, listType string, request *model.ListRequest)) *Client_PutList_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*model.ListRequest))
+	})
+	return _c
+}
+
+func (_c *Client_PutList_Call) Return(err error) *Client_PutList_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_PutList_Call) RunAndReturn(run func(ctx context.Context, address string, listType string, request *model.ListRequest) error) *Client_PutList_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ready provides a mock function for the type Client
+func (_mock *Client) Ready(ctx context.Context) error {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ready")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Client_Ready_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ready'
+type Client_Ready_Call struct {
+	*mock.Call
+}
+
+// Ready is a helper method to define mock.On call
+//   - ctx
+func (_e *Client_Expecter) Ready(ctx interface{}) *Client_Ready_Call {
+	return &Client_Ready_Call{Call: _e.mock.On("Ready", ctx)}
+}
+
+func (_c *Client_Ready_Call) Run(run func(ctx context.Context)) *Client_Ready_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_Ready_Call) Return(err error) *Client_Ready_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Client_Ready_Call) RunAndReturn(run func(ctx context.Context) error) *Client_Ready_Call {
 	_c.Call.Return(run)
 	return _c
 }
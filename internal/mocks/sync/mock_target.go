@@ -5,7 +5,9 @@
 package sync
 
 import (
+	"context"
 	"github.com/lovelaze/nebula-sync/internal/config"
+	sync0 "github.com/lovelaze/nebula-sync/internal/sync"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -36,17 +38,108 @@ func (_m *Target) EXPECT() *Target_Expecter {
 	return &Target_Expecter{mock: &_m.Mock}
 }
 
+// Close provides a mock function for the type Target
+func (_mock *Target) Close(ctx context.Context) {
+	_mock.Called(ctx)
+	return
+}
+
+// Target_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type Target_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+//   - ctx
+func (_e *Target_Expecter) Close(ctx interface{}) *Target_Close_Call {
+	return &Target_Close_Call{Call: _e.mock.On("Close", ctx)}
+}
+
+func (_c *Target_Close_Call) Run(run func(ctx context.Context)) *Target_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Target_Close_Call) Return() *Target_Close_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Target_Close_Call) RunAndReturn(run func(ctx context.Context)) *Target_Close_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Detect provides a mock function for the type Target
+func (_mock *Target) Detect(ctx context.Context, sync *config.Sync) (*sync0.Drift, error) {
+	ret := _mock.Called(ctx, sync)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Detect")
+	}
+
+	var r0 *sync0.Drift
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *config.Sync) (*sync0.Drift, error)); ok {
+		return returnFunc(ctx, sync)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *config.Sync) *sync0.Drift); ok {
+		r0 = returnFunc(ctx, sync)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sync0.Drift)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *config.Sync) error); ok {
+		r1 = returnFunc(ctx, sync)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Target_Detect_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Detect'
+type Target_Detect_Call struct {
+	*mock.Call
+}
+
+// Detect is a helper method to define mock.On call
+//   - ctx
+//   - sync
+func (_e *Target_Expecter) Detect(ctx interface{}, sync interface{}) *Target_Detect_Call {
+	return &Target_Detect_Call{Call: _e.mock.On("Detect", ctx, sync)}
+}
+
+func (_c *Target_Detect_Call) Run(run func(ctx context.Context, sync *config.Sync)) *Target_Detect_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*config.Sync))
+	})
+	return _c
+}
+
+func (_c *Target_Detect_Call) Return(drift *sync0.Drift, err error) *Target_Detect_Call {
+	_c.Call.Return(drift, err)
+	return _c
+}
+
+func (_c *Target_Detect_Call) RunAndReturn(run func(ctx context.Context, sync *config.Sync) (*sync0.Drift, error)) *Target_Detect_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FullSync provides a mock function for the type Target
-func (_mock *Target) FullSync(sync *config.Sync) error {
-	ret := _mock.Called(sync)
+func (_mock *Target) FullSync(ctx context.Context, sync *config.Sync) error {
+	ret := _mock.Called(ctx, sync)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FullSync")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*config.Sync) error); ok {
-		r0 = returnFunc(sync)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *config.Sync) error); ok {
+		r0 = returnFunc(ctx, sync)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -59,14 +152,15 @@ type Target_FullSync_Call struct {
 }
 
 // FullSync is a helper method to define mock.On call
+//   - ctx
 //   - sync
-func (_e *Target_Expecter) FullSync(sync interface{}) *Target_FullSync_Call {
-	return &Target_FullSync_Call{Call: _e.mock.On("FullSync", sync)}
+func (_e *Target_Expecter) FullSync(ctx interface{}, sync interface{}) *Target_FullSync_Call {
+	return &Target_FullSync_Call{Call: _e.mock.On("FullSync", ctx, sync)}
 }
 
-func (_c *Target_FullSync_Call) Run(run func(sync *config.Sync)) *Target_FullSync_Call {
+func (_c *Target_FullSync_Call) Run(run func(ctx context.Context, sync *config.Sync)) *Target_FullSync_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*config.Sync))
+		run(args[0].(context.Context), args[1].(*config.Sync))
 	})
 	return _c
 }
@@ -76,22 +170,125 @@ func (_c *Target_FullSync_Call) Return(err error) *Target_FullSync_Call {
 	return _c
 }
 
-func (_c *Target_FullSync_Call) RunAndReturn(run func(sync *config.Sync) error) *Target_FullSync_Call {
+func (_c *Target_FullSync_Call) RunAndReturn(run func(ctx context.Context, sync *config.Sync) error) *Target_FullSync_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Plan provides a mock function for the type Target
+func (_mock *Target) Plan(ctx context.Context, sync *config.Sync) (*sync0.Plan, error) {
+	ret := _mock.Called(ctx, sync)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Plan")
+	}
+
+	var r0 *sync0.Plan
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *config.Sync) (*sync0.Plan, error)); ok {
+		return returnFunc(ctx, sync)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *config.Sync) *sync0.Plan); ok {
+		r0 = returnFunc(ctx, sync)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sync0.Plan)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *config.Sync) error); ok {
+		r1 = returnFunc(ctx, sync)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// Target_Plan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Plan'
+type Target_Plan_Call struct {
+	*mock.Call
+}
+
+// Plan is a helper method to define mock.On call
+//   - ctx
+//   - sync
+func (_e *Target_Expecter) Plan(ctx interface{}, sync interface{}) *Target_Plan_Call {
+	return &Target_Plan_Call{Call: _e.mock.On("Plan", ctx, sync)}
+}
+
+func (_c *Target_Plan_Call) Run(run func(ctx context.Context, sync *config.Sync)) *Target_Plan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*config.Sync))
+	})
+	return _c
+}
+
+func (_c *Target_Plan_Call) Return(plan *sync0.Plan, err error) *Target_Plan_Call {
+	_c.Call.Return(plan, err)
+	return _c
+}
+
+func (_c *Target_Plan_Call) RunAndReturn(run func(ctx context.Context, sync *config.Sync) (*sync0.Plan, error)) *Target_Plan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RunGravity provides a mock function for the type Target
+func (_mock *Target) RunGravity(ctx context.Context, sync *config.Sync) error {
+	ret := _mock.Called(ctx, sync)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunGravity")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *config.Sync) error); ok {
+		r0 = returnFunc(ctx, sync)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// Target_RunGravity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunGravity'
+type Target_RunGravity_Call struct {
+	*mock.Call
+}
+
+// RunGravity is a helper method to define mock.On call
+//   - ctx
+//   - sync
+func (_e *Target_Expecter) RunGravity(ctx interface{}, sync interface{}) *Target_RunGravity_Call {
+	return &Target_RunGravity_Call{Call: _e.mock.On("RunGravity", ctx, sync)}
+}
+
+func (_c *Target_RunGravity_Call) Run(run func(ctx context.Context, sync *config.Sync)) *Target_RunGravity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*config.Sync))
+	})
+	return _c
+}
+
+func (_c *Target_RunGravity_Call) Return(err error) *Target_RunGravity_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Target_RunGravity_Call) RunAndReturn(run func(ctx context.Context, sync *config.Sync) error) *Target_RunGravity_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // SelectiveSync provides a mock function for the type Target
-func (_mock *Target) SelectiveSync(sync *config.Sync) error {
-	ret := _mock.Called(sync)
+func (_mock *Target) SelectiveSync(ctx context.Context, sync *config.Sync) error {
+	ret := _mock.Called(ctx, sync)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SelectiveSync")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*config.Sync) error); ok {
-		r0 = returnFunc(sync)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *config.Sync) error); ok {
+		r0 = returnFunc(ctx, sync)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -104,14 +301,15 @@ type Target_SelectiveSync_Call struct {
 }
 
 // SelectiveSync is a helper method to define mock.On call
+//   - ctx
 //   - sync
-func (_e *Target_Expecter) SelectiveSync(sync interface{}) *Target_SelectiveSync_Call {
-	return &Target_SelectiveSync_Call{Call: _e.mock.On("SelectiveSync", sync)}
+func (_e *Target_Expecter) SelectiveSync(ctx interface{}, sync interface{}) *Target_SelectiveSync_Call {
+	return &Target_SelectiveSync_Call{Call: _e.mock.On("SelectiveSync", ctx, sync)}
 }
 
-func (_c *Target_SelectiveSync_Call) Run(run func(sync *config.Sync)) *Target_SelectiveSync_Call {
+func (_c *Target_SelectiveSync_Call) Run(run func(ctx context.Context, sync *config.Sync)) *Target_SelectiveSync_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*config.Sync))
+		run(args[0].(context.Context), args[1].(*config.Sync))
 	})
 	return _c
 }
@@ -121,7 +319,7 @@ func (_c *Target_SelectiveSync_Call) Return(err error) *Target_SelectiveSync_Cal
 	return _c
 }
 
-func (_c *Target_SelectiveSync_Call) RunAndReturn(run func(sync *config.Sync) error) *Target_SelectiveSync_Call {
+func (_c *Target_SelectiveSync_Call) RunAndReturn(run func(ctx context.Context, sync *config.Sync) error) *Target_SelectiveSync_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -9,24 +9,34 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/pihole/totp"
 	"github.com/lovelaze/nebula-sync/version"
 )
 
 var userAgent = fmt.Sprintf("nebula-sync/%s", version.Version)
 
+// ErrTOTPRequired is returned when a Pi-hole has two-factor authentication enabled but no TOTP secret is configured.
+var ErrTOTPRequired = errors.New("two-factor authentication is enabled, but no TOTP secret is configured")
+
 type Client interface {
-	PostAuth() error
-	DeleteSession() error
-	GetTeleporter() ([]byte, error)
-	PostTeleporter(payload []byte, teleporterRequest *model.PostTeleporterRequest) error
-	GetConfig() (configResponse *model.ConfigResponse, err error)
-	PatchConfig(patchRequest *model.PatchConfigRequest) error
-	PostRunGravity() error
+	GravityAPI
+
+	PostAuth(ctx context.Context) error
+	DeleteSession(ctx context.Context) error
+	GetTeleporter(ctx context.Context) ([]byte, error)
+	PostTeleporter(ctx context.Context, payload []byte, teleporterRequest *model.PostTeleporterRequest) error
+	GetConfig(ctx context.Context) (configResponse *model.ConfigResponse, err error)
+	PatchConfig(ctx context.Context, patchRequest *model.PatchConfigRequest) error
+	PostRunGravity(ctx context.Context) error
+	GetVersion(ctx context.Context) (*model.VersionResponse, error)
+	Ready(ctx context.Context) error
+	HasSession() bool
 	String() string
 	APIPath(target string) string
 }
@@ -45,13 +55,20 @@ type client struct {
 	auth       auth
 	logger     *zerolog.Logger
 	httpClient *http.Client
+	// totpStep is the time step of the last TOTP code sent, Pi-hole rejects a code that was already used
+	totpStep uint64
 }
 
+// sessionMargin is subtracted from the expiry of a session, so it is not reused just before Pi-hole drops it.
+const sessionMargin = 10 * time.Second
+
 type auth struct {
 	sid      string
 	csrf     string
 	validity int
 	valid    bool
+	// expires is when Pi-hole drops the session, validity is extended by every request made with it
+	expires time.Time
 }
 
 func (a *auth) verify() error {
@@ -62,17 +79,26 @@ func (a *auth) verify() error {
 	return nil
 }
 
-func (client *client) PostAuth() error {
+func (client *client) PostAuth(ctx context.Context) error {
 	client.logger.Debug().Msg("PostAuth")
 	authResponse := model.AuthResponse{}
 
-	reqBytes, err := json.Marshal(model.AuthRequest{Password: client.piHole.Password})
+	authRequest := model.AuthRequest{Password: client.piHole.Password}
+	if client.piHole.TOTPSecret != "" {
+		code, err := client.totpCode(ctx)
+		if err != nil {
+			return client.wrapError(err, nil)
+		}
+		authRequest.TOTP = &code
+	}
+
+	reqBytes, err := json.Marshal(authRequest)
 	if err != nil {
 		return client.wrapError(err, nil)
 	}
 
 	req, err := http.NewRequestWithContext(
-		context.Background(),
+		ctx,
 		http.MethodPost,
 		client.APIPath("/auth"),
 		bytes.NewReader(reqBytes),
@@ -96,6 +122,9 @@ func (client *client) PostAuth() error {
 	}
 
 	if err := successfulHTTPStatus(response.StatusCode, body); err != nil {
+		if client.piHole.TOTPSecret == "" && client.totpEnabled(ctx) {
+			return client.wrapError(ErrTOTPRequired, req)
+		}
 		return client.wrapError(err, req)
 	}
 
@@ -108,12 +137,61 @@ func (client *client) PostAuth() error {
 		csrf:     authResponse.Session.Csrf,
 		validity: authResponse.Session.Validity,
 		valid:    authResponse.Session.Valid,
+		expires:  time.Now().Add(time.Duration(authResponse.Session.Validity) * time.Second),
 	}
 
 	return client.auth.verify()
 }
 
-func (client *client) DeleteSession() error {
+// totpCode returns the current TOTP code. If the code of the current time step was already sent, it waits for the
+// next one.
+func (client *client) totpCode(ctx context.Context) (int, error) {
+	step := totp.Step(time.Now())
+	if step <= client.totpStep {
+		client.logger.Debug().Msg("TOTP code already used, waiting for the next one")
+
+		timer := time.NewTimer(time.Until(totp.Start(client.totpStep + 1)))
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+		step = client.totpStep + 1
+	}
+
+	code, err := totp.Code(client.piHole.TOTPSecret, step)
+	if err != nil {
+		return 0, err
+	}
+
+	client.totpStep = step
+	return code, nil
+}
+
+// totpEnabled reports whether the Pi-hole requires a TOTP code to log in.
+func (client *client) totpEnabled(ctx context.Context) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.APIPath("auth"), nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	response, err := client.httpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer response.Body.Close()
+
+	authResponse := model.AuthResponse{}
+	if err := json.NewDecoder(response.Body).Decode(&authResponse); err != nil {
+		return false
+	}
+
+	return authResponse.Session.Totp
+}
+
+func (client *client) DeleteSession(ctx context.Context) error {
 	client.logger.Debug().Msg("Delete session")
 	if err := client.auth.verify(); err != nil {
 		return client.wrapError(err, nil)
@@ -124,7 +202,7 @@ func (client *client) DeleteSession() error {
 		return nil
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodDelete, client.APIPath("auth"), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, client.APIPath("auth"), nil)
 	if err != nil {
 		return client.wrapError(err, req)
 	}
@@ -147,22 +225,63 @@ func (client *client) DeleteSession() error {
 		return client.wrapError(err, req)
 	}
 
+	client.auth = auth{}
 	return nil
 }
 
-func (client *client) GetTeleporter() ([]byte, error) {
+// HasSession reports whether the client holds a session that can be reused without logging in again.
+func (client *client) HasSession() bool {
+	return client.auth.valid && time.Now().Add(sessionMargin).Before(client.auth.expires)
+}
+
+// do sends an authenticated request. When Pi-hole answers 401 because the session expired, the client logs in again
+// and retries the request once with the new session.
+func (client *client) do(req *http.Request) (*http.Response, error) {
+	response, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusUnauthorized {
+		client.auth.extend()
+		return response, nil
+	}
+	response.Body.Close()
+
+	client.logger.Debug().Msg("Session rejected, authenticating again")
+	if err := client.PostAuth(req.Context()); err != nil {
+		return nil, fmt.Errorf("authenticate again: %w", err)
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	retry.Header.Set("Sid", client.auth.sid)
+
+	return client.httpClient.Do(retry)
+}
+
+func (a *auth) extend() {
+	if a.valid {
+		a.expires = time.Now().Add(time.Duration(a.validity) * time.Second)
+	}
+}
+
+func (client *client) GetTeleporter(ctx context.Context) ([]byte, error) {
 	client.logger.Debug().Msg("Get teleporter")
 	if err := client.auth.verify(); err != nil {
 		return nil, client.wrapError(err, nil)
 	}
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, client.APIPath("teleporter"), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.APIPath("teleporter"), nil)
 	if err != nil {
 		return nil, client.wrapError(err, req)
 	}
 	req.Header.Set("Sid", client.auth.sid)
 	req.Header.Set("User-Agent", userAgent)
 
-	response, err := client.httpClient.Do(req)
+	response, err := client.do(req)
 	if err != nil {
 		return nil, client.wrapError(err, req)
 	}
@@ -180,7 +299,11 @@ func (client *client) GetTeleporter() ([]byte, error) {
 	return body, nil
 }
 
-func (client *client) PostTeleporter(payload []byte, teleporterRequest *model.PostTeleporterRequest) error {
+func (client *client) PostTeleporter(
+	ctx context.Context,
+	payload []byte,
+	teleporterRequest *model.PostTeleporterRequest,
+) error {
 	client.logger.Debug().Any("payload", teleporterRequest).Msg("Post teleporter")
 
 	if err := client.auth.verify(); err != nil {
@@ -209,7 +332,7 @@ func (client *client) PostTeleporter(payload []byte, teleporterRequest *model.Po
 		return client.wrapError(err, nil)
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, client.APIPath("teleporter"), &requestBody)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.APIPath("teleporter"), &requestBody)
 	if err != nil {
 		return client.wrapError(err, req)
 	}
@@ -217,7 +340,7 @@ func (client *client) PostTeleporter(payload []byte, teleporterRequest *model.Po
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	req.Header.Set("User-Agent", userAgent)
 
-	response, err := client.httpClient.Do(req)
+	response, err := client.do(req)
 	if err != nil {
 		return client.wrapError(err, req)
 	}
@@ -235,21 +358,21 @@ func (client *client) PostTeleporter(payload []byte, teleporterRequest *model.Po
 	return nil
 }
 
-func (client *client) GetConfig() (*model.ConfigResponse, error) {
+func (client *client) GetConfig(ctx context.Context) (*model.ConfigResponse, error) {
 	var configResponse model.ConfigResponse
 	client.logger.Debug().Msg("Get config")
 	if err := client.auth.verify(); err != nil {
 		return &configResponse, client.wrapError(err, nil)
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, client.APIPath("config"), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.APIPath("config"), nil)
 	if err != nil {
 		return &configResponse, client.wrapError(err, req)
 	}
 	req.Header.Set("Sid", client.auth.sid)
 	req.Header.Set("User-Agent", userAgent)
 
-	response, err := client.httpClient.Do(req)
+	response, err := client.do(req)
 	if err != nil {
 		return &configResponse, client.wrapError(err, req)
 	}
@@ -271,7 +394,7 @@ func (client *client) GetConfig() (*model.ConfigResponse, error) {
 	return &configResponse, client.wrapError(err, req)
 }
 
-func (client *client) PatchConfig(patchRequest *model.PatchConfigRequest) error {
+func (client *client) PatchConfig(ctx context.Context, patchRequest *model.PatchConfigRequest) error {
 	client.logger.Debug().Any("payload", patchRequest).Msgf("Patch config")
 	if err := client.auth.verify(); err != nil {
 		return client.wrapError(err, nil)
@@ -283,7 +406,7 @@ func (client *client) PatchConfig(patchRequest *model.PatchConfigRequest) error
 	}
 
 	req, err := http.NewRequestWithContext(
-		context.Background(),
+		ctx,
 		http.MethodPatch,
 		client.APIPath("config"),
 		bytes.NewReader(reqBytes),
@@ -294,7 +417,7 @@ func (client *client) PatchConfig(patchRequest *model.PatchConfigRequest) error
 	req.Header.Set("Sid", client.auth.sid)
 	req.Header.Set("User-Agent", userAgent)
 
-	response, err := client.httpClient.Do(req)
+	response, err := client.do(req)
 	if err != nil {
 		return client.wrapError(err, req)
 	}
@@ -312,19 +435,47 @@ func (client *client) PatchConfig(patchRequest *model.PatchConfigRequest) error
 	return nil
 }
 
-func (client *client) PostRunGravity() error {
+func (client *client) PostRunGravity(ctx context.Context) error {
 	client.logger.Debug().Msg("Post run gravity")
 	if err := client.auth.verify(); err != nil {
 		return client.wrapError(err, nil)
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, client.APIPath("action/gravity"), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.APIPath("action/gravity"), nil)
 	if err != nil {
 		return client.wrapError(err, req)
 	}
 	req.Header.Set("Sid", client.auth.sid)
 	req.Header.Set("User-Agent", userAgent)
 
+	response, err := client.do(req)
+	if err != nil {
+		return client.wrapError(err, req)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return client.wrapError(err, req)
+	}
+
+	if err := successfulHTTPStatus(response.StatusCode, body); err != nil {
+		return client.wrapError(err, req)
+	}
+
+	return nil
+}
+
+// Ready returns nil once the API answers requests again, e.g. after FTL restarted. It does not need a session,
+// an unauthenticated answer counts as ready.
+func (client *client) Ready(ctx context.Context) error {
+	client.logger.Debug().Msg("Ready")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.APIPath("auth"), nil)
+	if err != nil {
+		return client.wrapError(err, req)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
 	response, err := client.httpClient.Do(req)
 	if err != nil {
 		return client.wrapError(err, req)
@@ -336,6 +487,9 @@ func (client *client) PostRunGravity() error {
 		return client.wrapError(err, req)
 	}
 
+	if response.StatusCode == http.StatusUnauthorized {
+		return nil
+	}
 	if err := successfulHTTPStatus(response.StatusCode, body); err != nil {
 		return client.wrapError(err, req)
 	}
@@ -343,6 +497,16 @@ func (client *client) PostRunGravity() error {
 	return nil
 }
 
+// GetVersion returns the versions of the Pi-hole components, e.g. core, web and FTL.
+func (client *client) GetVersion(ctx context.Context) (*model.VersionResponse, error) {
+	client.logger.Debug().Msg("Get version")
+	versionResponse := model.VersionResponse{}
+	if err := client.send(ctx, http.MethodGet, client.APIPath("info/version"), nil, &versionResponse); err != nil {
+		return nil, err
+	}
+	return &versionResponse, nil
+}
+
 func (client *client) String() string {
 	return client.piHole.URL.String()
 }
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -13,6 +16,7 @@ import (
 
 	"github.com/lovelaze/nebula-sync/e2e"
 	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/pihole/totp"
 )
 
 const (
@@ -31,7 +35,7 @@ type clientTestSuite struct {
 
 func (suite *clientTestSuite) SetupTest() {
 	client := createClient(piHole)
-	err := client.PostAuth()
+	err := client.PostAuth(context.Background())
 	suite.Require().NoError(err)
 	suite.client = client
 }
@@ -41,27 +45,44 @@ func TestClientIntegration(t *testing.T) {
 }
 
 func (suite *clientTestSuite) TestClient_Authenticate() {
-	err := suite.client.PostAuth()
+	err := suite.client.PostAuth(context.Background())
 
 	suite.Require().NoError(err)
 }
 
 func (suite *clientTestSuite) TestClient_DeleteSession() {
-	err := suite.client.DeleteSession()
+	err := suite.client.DeleteSession(context.Background())
 
 	suite.Require().NoError(err)
 }
 
+func (suite *clientTestSuite) TestClient_DeleteSession_clearsSession() {
+	suite.Require().True(suite.client.HasSession())
+
+	suite.Require().NoError(suite.client.DeleteSession(context.Background()))
+
+	suite.False(suite.client.HasSession())
+}
+
+func (suite *clientTestSuite) TestClient_reauthenticate() {
+	suite.client.(*client).auth.sid = "expired"
+
+	_, err := suite.client.GetConfig(context.Background())
+
+	suite.Require().NoError(err)
+	suite.NotEqual("expired", suite.client.(*client).auth.sid)
+}
+
 func (suite *clientTestSuite) TestClient_GetTeleporter() {
-	payload, err := suite.client.GetTeleporter()
+	payload, err := suite.client.GetTeleporter(context.Background())
 
 	suite.Require().NoError(err)
 	suite.NotNil(suite.T(), payload)
 }
 
 func (suite *clientTestSuite) TestClient_PostTeleporter() {
-	payload, _ := suite.client.GetTeleporter()
-	err := suite.client.PostTeleporter(payload, &model.PostTeleporterRequest{
+	payload, _ := suite.client.GetTeleporter(context.Background())
+	err := suite.client.PostTeleporter(context.Background(), payload, &model.PostTeleporterRequest{
 		Config:     true,
 		DHCPLeases: true,
 		Gravity: model.PostGravityRequest{
@@ -79,7 +100,7 @@ func (suite *clientTestSuite) TestClient_PostTeleporter() {
 }
 
 func (suite *clientTestSuite) TestClient_GetConfig() {
-	conf, err := suite.client.GetConfig()
+	conf, err := suite.client.GetConfig(context.Background())
 
 	suite.Require().NoError(err)
 	suite.NotNil(suite.T(), conf)
@@ -97,13 +118,26 @@ func (suite *clientTestSuite) TestClient_PatchConfig() {
 			Debug:    nil,
 		},
 	}
-	err := suite.client.PatchConfig(&request)
+	err := suite.client.PatchConfig(context.Background(), &request)
 
 	suite.Require().NoError(err)
 }
 
 func (suite *clientTestSuite) TestClient_PostRunGravity() {
-	err := suite.client.PostRunGravity()
+	err := suite.client.PostRunGravity(context.Background())
+
+	suite.Require().NoError(err)
+}
+
+func (suite *clientTestSuite) TestClient_GetVersion() {
+	version, err := suite.client.GetVersion(context.Background())
+
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(version.Version.FTL.Local.Version, "v6."))
+}
+
+func (suite *clientTestSuite) TestClient_Ready() {
+	err := createClient(piHole).Ready(context.Background())
 
 	suite.Require().NoError(err)
 }
@@ -139,6 +173,40 @@ func Test_auth_verify(t *testing.T) {
 	require.NoError(t, a.verify())
 }
 
+func Test_client_HasSession(t *testing.T) {
+	c := &client{}
+	assert.False(t, c.HasSession())
+
+	c.auth = auth{valid: true, validity: 300, expires: time.Now().Add(300 * time.Second)}
+	assert.True(t, c.HasSession())
+
+	c.auth.expires = time.Now().Add(sessionMargin / 2)
+	assert.False(t, c.HasSession())
+
+	c.auth.extend()
+	assert.True(t, c.HasSession())
+}
+
+func Test_client_totpCode(t *testing.T) {
+	c := &client{
+		piHole: model.PiHole{TOTPSecret: "JBSWY3DPEHPK3PXP"},
+		logger: &log.Logger,
+	}
+
+	code, err := c.totpCode(context.Background())
+	require.NoError(t, err)
+
+	expected, err := totp.Code("JBSWY3DPEHPK3PXP", c.totpStep)
+	require.NoError(t, err)
+	assert.Equal(t, expected, code)
+
+	// the code of this step was used, the next one is only available once it begins
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.totpCode(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
 func createClient(container tc.Container) Client {
 	apiPort, err := container.MappedPort(context.Background(), "80/tcp")
 	if err != nil {
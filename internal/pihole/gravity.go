@@ -0,0 +1,246 @@
+package pihole
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+)
+
+// GravityAPI manages the entries of the gravity database one by one, instead of importing it with a teleporter.
+type GravityAPI interface {
+	GetGroups(ctx context.Context) ([]model.Group, error)
+	PostGroup(ctx context.Context, request *model.GroupRequest) error
+	PutGroup(ctx context.Context, name string, request *model.GroupRequest) error
+	DeleteGroup(ctx context.Context, name string) error
+	DeleteGroups(ctx context.Context, names []string) error
+
+	GetLists(ctx context.Context) ([]model.List, error)
+	PostList(ctx context.Context, listType string, request *model.ListRequest) error
+	PutList(ctx context.Context, address, listType string, request *model.ListRequest) error
+	DeleteList(ctx context.Context, address, listType string) error
+	DeleteLists(ctx context.Context, items []model.BatchDeleteItem) error
+
+	GetDomains(ctx context.Context, domainType, kind string) ([]model.Domain, error)
+	PostDomain(ctx context.Context, domainType, kind string, request *model.DomainRequest) error
+	PutDomain(ctx context.Context, domain, domainType, kind string, request *model.DomainRequest) error
+	DeleteDomain(ctx context.Context, domain, domainType, kind string) error
+	DeleteDomains(ctx context.Context, items []model.BatchDeleteItem) error
+
+	GetClients(ctx context.Context) ([]model.Client, error)
+	PostClient(ctx context.Context, request *model.ClientRequest) error
+	PutClient(ctx context.Context, client string, request *model.ClientRequest) error
+	DeleteClient(ctx context.Context, client string) error
+	DeleteClients(ctx context.Context, clients []string) error
+}
+
+func (client *client) GetGroups(ctx context.Context) ([]model.Group, error) {
+	client.logger.Debug().Msg("Get groups")
+	response := model.GroupsResponse{}
+	if err := client.send(ctx, http.MethodGet, client.APIPath("groups"), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Groups, nil
+}
+
+func (client *client) PostGroup(ctx context.Context, request *model.GroupRequest) error {
+	client.logger.Debug().Any("payload", request).Msg("Post group")
+	return client.sendProcessed(ctx, http.MethodPost, client.APIPath("groups"), request)
+}
+
+func (client *client) PutGroup(ctx context.Context, name string, request *model.GroupRequest) error {
+	client.logger.Debug().Str("group", name).Any("payload", request).Msg("Put group")
+	return client.sendProcessed(ctx, http.MethodPut, client.itemPath("groups", name), request)
+}
+
+func (client *client) DeleteGroup(ctx context.Context, name string) error {
+	client.logger.Debug().Str("group", name).Msg("Delete group")
+	return client.send(ctx, http.MethodDelete, client.itemPath("groups", name), nil, nil)
+}
+
+func (client *client) DeleteGroups(ctx context.Context, names []string) error {
+	client.logger.Debug().Strs("groups", names).Msg("Delete groups")
+	return client.send(ctx, http.MethodPost, client.APIPath("groups:batchDelete"), batchItems(names), nil)
+}
+
+func (client *client) GetLists(ctx context.Context) ([]model.List, error) {
+	client.logger.Debug().Msg("Get lists")
+	response := model.ListsResponse{}
+	if err := client.send(ctx, http.MethodGet, client.APIPath("lists"), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Lists, nil
+}
+
+func (client *client) PostList(ctx context.Context, listType string, request *model.ListRequest) error {
+	client.logger.Debug().Str("type", listType).Any("payload", request).Msg("Post list")
+	return client.sendProcessed(ctx, http.MethodPost, withType(client.APIPath("lists"), listType), request)
+}
+
+func (client *client) PutList(ctx context.Context, address, listType string, request *model.ListRequest) error {
+	client.logger.Debug().Str("list", address).Str("type", listType).Any("payload", request).Msg("Put list")
+	return client.sendProcessed(ctx, http.MethodPut, withType(client.itemPath("lists", address), listType), request)
+}
+
+func (client *client) DeleteList(ctx context.Context, address, listType string) error {
+	client.logger.Debug().Str("list", address).Str("type", listType).Msg("Delete list")
+	return client.send(ctx, http.MethodDelete, withType(client.itemPath("lists", address), listType), nil, nil)
+}
+
+func (client *client) DeleteLists(ctx context.Context, items []model.BatchDeleteItem) error {
+	client.logger.Debug().Any("lists", items).Msg("Delete lists")
+	return client.send(ctx, http.MethodPost, client.APIPath("lists:batchDelete"), items, nil)
+}
+
+// GetDomains returns the domains of domainType and kind, all domains if both are empty.
+func (client *client) GetDomains(ctx context.Context, domainType, kind string) ([]model.Domain, error) {
+	client.logger.Debug().Str("type", domainType).Str("kind", kind).Msg("Get domains")
+	response := model.DomainsResponse{}
+	if err := client.send(ctx, http.MethodGet, client.itemPath("domains", domainType, kind), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Domains, nil
+}
+
+func (client *client) PostDomain(ctx context.Context, domainType, kind string, request *model.DomainRequest) error {
+	client.logger.Debug().Str("type", domainType).Str("kind", kind).Any("payload", request).Msg("Post domain")
+	return client.sendProcessed(ctx, http.MethodPost, client.itemPath("domains", domainType, kind), request)
+}
+
+func (client *client) PutDomain(ctx context.Context, domain, domainType, kind string, request *model.DomainRequest) error {
+	client.logger.Debug().
+		Str("domain", domain).
+		Str("type", domainType).
+		Str("kind", kind).
+		Any("payload", request).
+		Msg("Put domain")
+	return client.sendProcessed(ctx, http.MethodPut, client.itemPath("domains", domainType, kind, domain), request)
+}
+
+func (client *client) DeleteDomain(ctx context.Context, domain, domainType, kind string) error {
+	client.logger.Debug().Str("domain", domain).Str("type", domainType).Str("kind", kind).Msg("Delete domain")
+	return client.send(ctx, http.MethodDelete, client.itemPath("domains", domainType, kind, domain), nil, nil)
+}
+
+func (client *client) DeleteDomains(ctx context.Context, items []model.BatchDeleteItem) error {
+	client.logger.Debug().Any("domains", items).Msg("Delete domains")
+	return client.send(ctx, http.MethodPost, client.APIPath("domains:batchDelete"), items, nil)
+}
+
+func (client *client) GetClients(ctx context.Context) ([]model.Client, error) {
+	client.logger.Debug().Msg("Get clients")
+	response := model.ClientsResponse{}
+	if err := client.send(ctx, http.MethodGet, client.APIPath("clients"), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Clients, nil
+}
+
+func (client *client) PostClient(ctx context.Context, request *model.ClientRequest) error {
+	client.logger.Debug().Any("payload", request).Msg("Post client")
+	return client.sendProcessed(ctx, http.MethodPost, client.APIPath("clients"), request)
+}
+
+func (client *client) PutClient(ctx context.Context, gravityClient string, request *model.ClientRequest) error {
+	client.logger.Debug().Str("gravity_client", gravityClient).Any("payload", request).Msg("Put client")
+	return client.sendProcessed(ctx, http.MethodPut, client.itemPath("clients", gravityClient), request)
+}
+
+func (client *client) DeleteClient(ctx context.Context, gravityClient string) error {
+	client.logger.Debug().Str("gravity_client", gravityClient).Msg("Delete client")
+	return client.send(ctx, http.MethodDelete, client.itemPath("clients", gravityClient), nil, nil)
+}
+
+func (client *client) DeleteClients(ctx context.Context, clients []string) error {
+	client.logger.Debug().Strs("gravity_clients", clients).Msg("Delete clients")
+	return client.send(ctx, http.MethodPost, client.APIPath("clients:batchDelete"), batchItems(clients), nil)
+}
+
+// send makes an authenticated request with payload as JSON body, if not nil, and decodes the JSON response into result,
+// if not nil.
+func (client *client) send(ctx context.Context, method, target string, payload, result any) error {
+	if err := client.auth.verify(); err != nil {
+		return client.wrapError(err, nil)
+	}
+
+	var body io.Reader
+	if payload != nil {
+		reqBytes, err := json.Marshal(payload)
+		if err != nil {
+			return client.wrapError(err, nil)
+		}
+		body = bytes.NewReader(reqBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return client.wrapError(err, req)
+	}
+	req.Header.Set("Sid", client.auth.sid)
+	req.Header.Set("User-Agent", userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := client.do(req)
+	if err != nil {
+		return client.wrapError(err, req)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return client.wrapError(err, req)
+	}
+
+	if err := successfulHTTPStatus(response.StatusCode, responseBody); err != nil {
+		return client.wrapError(err, req)
+	}
+
+	if result != nil && len(responseBody) > 0 {
+		if err := json.Unmarshal(responseBody, result); err != nil {
+			return client.wrapError(err, req)
+		}
+	}
+
+	return nil
+}
+
+// sendProcessed sends a create or update request and fails if Pi-hole reports an item it could not process.
+func (client *client) sendProcessed(ctx context.Context, method, target string, payload any) error {
+	response := model.ProcessedResponse{}
+	if err := client.send(ctx, method, target, payload, &response); err != nil {
+		return err
+	}
+	return client.wrapError(response.Err(), nil)
+}
+
+// itemPath returns the API path of an entry, escaping every non-empty segment so e.g. list addresses can be used.
+func (client *client) itemPath(endpoint string, segments ...string) string {
+	path := client.APIPath(endpoint)
+	for _, segment := range segments {
+		if segment != "" {
+			path += "/" + url.PathEscape(segment)
+		}
+	}
+	return path
+}
+
+func withType(target, listType string) string {
+	if listType == "" {
+		return target
+	}
+	return target + "?type=" + url.QueryEscape(listType)
+}
+
+func batchItems(names []string) []model.BatchDeleteItem {
+	items := make([]model.BatchDeleteItem, 0, len(names))
+	for _, name := range names {
+		items = append(items, model.BatchDeleteItem{Item: name})
+	}
+	return items
+}
@@ -0,0 +1,87 @@
+package pihole
+
+import (
+	"context"
+	"slices"
+
+	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+)
+
+func (suite *clientTestSuite) TestClient_groups() {
+	ctx := context.Background()
+
+	suite.Require().NoError(suite.client.PostGroup(ctx, &model.GroupRequest{Name: "kids", Comment: "tablets", Enabled: true}))
+	suite.Require().NoError(suite.client.PutGroup(ctx, "kids", &model.GroupRequest{Name: "kids", Comment: "phones"}))
+
+	groups, err := suite.client.GetGroups(ctx)
+	suite.Require().NoError(err)
+	i := slices.IndexFunc(groups, func(group model.Group) bool { return group.Name == "kids" })
+	suite.Require().GreaterOrEqual(i, 0)
+	suite.Equal("phones", groups[i].Comment)
+	suite.False(groups[i].Enabled)
+
+	suite.Require().Error(suite.client.PostGroup(ctx, &model.GroupRequest{Name: "kids"}), "duplicate group")
+
+	suite.Require().NoError(suite.client.DeleteGroup(ctx, "kids"))
+	suite.Require().NoError(suite.client.PostGroup(ctx, &model.GroupRequest{Name: "guests"}))
+	suite.Require().NoError(suite.client.DeleteGroups(ctx, []string{"guests"}))
+}
+
+func (suite *clientTestSuite) TestClient_lists() {
+	ctx := context.Background()
+	address := "https://example.com/hosts.txt?format=plain"
+
+	suite.Require().NoError(suite.client.PostList(ctx, model.ListTypeBlock, &model.ListRequest{
+		Address: address,
+		Groups:  []int{0},
+		Enabled: true,
+	}))
+	suite.Require().NoError(suite.client.PutList(ctx, address, model.ListTypeBlock, &model.ListRequest{
+		Type:    model.ListTypeBlock,
+		Comment: "hosts",
+		Groups:  []int{0},
+	}))
+
+	lists, err := suite.client.GetLists(ctx)
+	suite.Require().NoError(err)
+	i := slices.IndexFunc(lists, func(list model.List) bool { return list.Address == address })
+	suite.Require().GreaterOrEqual(i, 0)
+	suite.Equal("hosts", lists[i].Comment)
+
+	suite.Require().NoError(suite.client.DeleteList(ctx, address, model.ListTypeBlock))
+}
+
+func (suite *clientTestSuite) TestClient_domains() {
+	ctx := context.Background()
+
+	suite.Require().NoError(suite.client.PostDomain(ctx, model.DomainTypeDeny, model.DomainKindExact, &model.DomainRequest{
+		Domain:  "ads.example.com",
+		Groups:  []int{0},
+		Enabled: true,
+	}))
+	suite.Require().NoError(suite.client.PutDomain(ctx, "ads.example.com", model.DomainTypeDeny, model.DomainKindExact,
+		&model.DomainRequest{Type: model.DomainTypeAllow, Kind: model.DomainKindExact, Groups: []int{0}, Enabled: true}))
+
+	domains, err := suite.client.GetDomains(ctx, model.DomainTypeAllow, model.DomainKindExact)
+	suite.Require().NoError(err)
+	suite.True(slices.ContainsFunc(domains, func(domain model.Domain) bool { return domain.Domain == "ads.example.com" }))
+
+	suite.Require().NoError(suite.client.DeleteDomains(ctx, []model.BatchDeleteItem{
+		{Item: "ads.example.com", Type: model.DomainTypeAllow, Kind: model.DomainKindExact},
+	}))
+}
+
+func (suite *clientTestSuite) TestClient_clients() {
+	ctx := context.Background()
+
+	suite.Require().NoError(suite.client.PostClient(ctx, &model.ClientRequest{Client: "192.168.1.0/24", Groups: []int{0}}))
+	suite.Require().NoError(suite.client.PutClient(ctx, "192.168.1.0/24", &model.ClientRequest{Comment: "lan", Groups: []int{0}}))
+
+	clients, err := suite.client.GetClients(ctx)
+	suite.Require().NoError(err)
+	i := slices.IndexFunc(clients, func(client model.Client) bool { return client.Client == "192.168.1.0/24" })
+	suite.Require().GreaterOrEqual(i, 0)
+	suite.Equal("lan", clients[i].Comment)
+
+	suite.Require().NoError(suite.client.DeleteClient(ctx, "192.168.1.0/24"))
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	ListTypeAllow = "allow"
+	ListTypeBlock = "block"
+
+	DomainTypeAllow = "allow"
+	DomainTypeDeny  = "deny"
+
+	DomainKindExact = "exact"
+	DomainKindRegex = "regex"
+)
+
+// Group is a group of the gravity database, as returned by /api/groups.
+type Group struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Comment      string `json:"comment"`
+	Enabled      bool   `json:"enabled"`
+	DateAdded    int64  `json:"date_added"`
+	DateModified int64  `json:"date_modified"`
+}
+
+type GroupRequest struct {
+	Name    string `json:"name"`
+	Comment string `json:"comment"`
+	Enabled bool   `json:"enabled"`
+}
+
+type GroupsResponse struct {
+	Groups []Group `json:"groups"`
+}
+
+// List is an allow or block list, as returned by /api/lists. Groups holds group IDs, which differ between Pi-holes.
+type List struct {
+	ID             int    `json:"id"`
+	Address        string `json:"address"`
+	Type           string `json:"type"`
+	Comment        string `json:"comment"`
+	Groups         []int  `json:"groups"`
+	Enabled        bool   `json:"enabled"`
+	DateAdded      int64  `json:"date_added"`
+	DateModified   int64  `json:"date_modified"`
+	DateUpdated    int64  `json:"date_updated"`
+	Number         int    `json:"number"`
+	InvalidDomains int    `json:"invalid_domains"`
+	ABPEntries     int    `json:"abp_entries"`
+	Status         int    `json:"status"`
+}
+
+// ListRequest creates or updates a list. Address is only sent when a list is created, Type only when it is updated.
+type ListRequest struct {
+	Address string `json:"address,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Comment string `json:"comment"`
+	Groups  []int  `json:"groups"`
+	Enabled bool   `json:"enabled"`
+}
+
+type ListsResponse struct {
+	Lists []List `json:"lists"`
+}
+
+// Domain is an exact or regex allow or deny entry, as returned by /api/domains.
+type Domain struct {
+	ID           int    `json:"id"`
+	Domain       string `json:"domain"`
+	Unicode      string `json:"unicode"`
+	Type         string `json:"type"`
+	Kind         string `json:"kind"`
+	Comment      string `json:"comment"`
+	Groups       []int  `json:"groups"`
+	Enabled      bool   `json:"enabled"`
+	DateAdded    int64  `json:"date_added"`
+	DateModified int64  `json:"date_modified"`
+}
+
+// DomainRequest creates or updates a domain. Domain is only sent when a domain is created, Type and Kind only when it
+// is updated, to move the domain to another type or kind.
+type DomainRequest struct {
+	Domain  string `json:"domain,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	Comment string `json:"comment"`
+	Groups  []int  `json:"groups"`
+	Enabled bool   `json:"enabled"`
+}
+
+type DomainsResponse struct {
+	Domains []Domain `json:"domains"`
+}
+
+// Client is a client of the gravity database, identified by an IP address, subnet, MAC address, hostname or interface.
+type Client struct {
+	ID           int    `json:"id"`
+	Client       string `json:"client"`
+	Name         string `json:"name"`
+	Comment      string `json:"comment"`
+	Groups       []int  `json:"groups"`
+	DateAdded    int64  `json:"date_added"`
+	DateModified int64  `json:"date_modified"`
+}
+
+// ClientRequest creates or updates a client. Client is only sent when a client is created.
+type ClientRequest struct {
+	Client  string `json:"client,omitempty"`
+	Comment string `json:"comment"`
+	Groups  []int  `json:"groups"`
+}
+
+type ClientsResponse struct {
+	Clients []Client `json:"clients"`
+}
+
+// BatchDeleteItem identifies an entry to delete with a batch delete. Type and Kind are required for lists and domains.
+type BatchDeleteItem struct {
+	Item string `json:"item"`
+	Type string `json:"type,omitempty"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// ProcessedResponse is the part of a create or update response that reports the outcome per item.
+type ProcessedResponse struct {
+	Processed *struct {
+		Success []struct {
+			Item string `json:"item"`
+		} `json:"success"`
+		Errors []struct {
+			Item  string `json:"item"`
+			Error string `json:"error"`
+		} `json:"errors"`
+	} `json:"processed"`
+}
+
+// Err returns the errors of the items Pi-hole did not process, nil if all succeeded.
+func (r *ProcessedResponse) Err() error {
+	if r.Processed == nil {
+		return nil
+	}
+
+	errs := make([]error, 0, len(r.Processed.Errors))
+	for _, processed := range r.Processed.Errors {
+		errs = append(errs, fmt.Errorf("%s: %s", processed.Item, processed.Error))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessedResponse_Err(t *testing.T) {
+	response := ProcessedResponse{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"groups": [],
+		"processed": {
+			"success": [{"item": "kids"}],
+			"errors": [{"item": "guests", "error": "UNIQUE constraint failed: group.name"}]
+		}
+	}`), &response))
+
+	assert.EqualError(t, response.Err(), "guests: UNIQUE constraint failed: group.name")
+
+	response = ProcessedResponse{}
+	require.NoError(t, json.Unmarshal([]byte(`{"processed": {"success": [{"item": "kids"}], "errors": []}}`), &response))
+	require.NoError(t, response.Err())
+
+	require.NoError(t, (&ProcessedResponse{}).Err())
+}
+
+func TestListRequest_omitsIdentity(t *testing.T) {
+	body, err := json.Marshal(ListRequest{Comment: "ads", Groups: []int{0}, Enabled: true})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"comment": "ads", "groups": [0], "enabled": true}`, string(body))
+}
@@ -12,6 +12,8 @@ import (
 type PiHole struct {
 	URL      *url.URL
 	Password string
+	// TOTPSecret is the base32 encoded secret of the Pi-hole's two-factor authentication, empty if it is disabled
+	TOTPSecret string
 }
 
 func NewPiHole(host, password string) PiHole {
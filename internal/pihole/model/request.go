@@ -2,6 +2,7 @@ package model
 
 type AuthRequest struct {
 	Password string `json:"password"`
+	TOTP     *int   `json:"totp,omitempty"`
 }
 
 type PostGravityRequest struct {
@@ -21,15 +22,58 @@ type PostTeleporterRequest struct {
 }
 
 type PatchConfig struct {
-	DNS      map[string]any `json:"dns"`
-	DHCP     map[string]any `json:"dhcp"`
-	NTP      map[string]any `json:"ntp"`
-	Resolver map[string]any `json:"resolver"`
-	Database map[string]any `json:"database"`
-	Misc     map[string]any `json:"misc"`
-	Debug    map[string]any `json:"debug"`
+	DNS       map[string]any `json:"dns"`
+	DHCP      map[string]any `json:"dhcp"`
+	NTP       map[string]any `json:"ntp"`
+	Resolver  map[string]any `json:"resolver"`
+	Database  map[string]any `json:"database"`
+	Webserver map[string]any `json:"webserver"`
+	Files     map[string]any `json:"files"`
+	Misc      map[string]any `json:"misc"`
+	Debug     map[string]any `json:"debug"`
 }
 
 type PatchConfigRequest struct {
 	Config PatchConfig `json:"config"`
 }
+
+func NewPatchConfig(sections map[string]any) PatchConfig {
+	section := func(name string) map[string]any {
+		value, _ := sections[name].(map[string]any)
+		return value
+	}
+
+	return PatchConfig{
+		DNS:       section("dns"),
+		DHCP:      section("dhcp"),
+		NTP:       section("ntp"),
+		Resolver:  section("resolver"),
+		Database:  section("database"),
+		Webserver: section("webserver"),
+		Files:     section("files"),
+		Misc:      section("misc"),
+		Debug:     section("debug"),
+	}
+}
+
+func (pc *PatchConfig) Map() map[string]any {
+	sections := map[string]map[string]any{
+		"dns":       pc.DNS,
+		"dhcp":      pc.DHCP,
+		"ntp":       pc.NTP,
+		"resolver":  pc.Resolver,
+		"database":  pc.Database,
+		"webserver": pc.Webserver,
+		"files":     pc.Files,
+		"misc":      pc.Misc,
+		"debug":     pc.Debug,
+	}
+
+	result := make(map[string]any)
+	for name, section := range sections {
+		if section != nil {
+			result[name] = section
+		}
+	}
+	return result
+}
@@ -0,0 +1,23 @@
+package model
+
+// VersionResponse holds the versions of the Pi-hole components, as returned by /api/info/version.
+type VersionResponse struct {
+	Version struct {
+		Core   ComponentVersion `json:"core"`
+		Web    ComponentVersion `json:"web"`
+		FTL    ComponentVersion `json:"ftl"`
+		Docker struct {
+			Local string `json:"local"`
+		} `json:"docker"`
+	} `json:"version"`
+}
+
+// ComponentVersion is the installed version of a Pi-hole component. Version is e.g. v6.0.4, or a development build
+// that is not tagged.
+type ComponentVersion struct {
+	Local struct {
+		Branch  string `json:"branch"`
+		Version string `json:"version"`
+		Hash    string `json:"hash"`
+	} `json:"local"`
+}
@@ -0,0 +1,77 @@
+// Package totp generates time-based one-time passwords as defined by RFC 6238, with the parameters Pi-hole uses:
+// HMAC-SHA1, 6 digits and a 30 second period.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec // RFC 6238 authenticators, including Pi-hole, use HMAC-SHA1
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Period is the time a code is valid for.
+	Period = 30 * time.Second
+	digits = 6
+)
+
+// Validate returns an error if secret is not a base32 encoded TOTP secret.
+func Validate(secret string) error {
+	_, err := decode(secret)
+	return err
+}
+
+// Step returns the number of the time step at falls into.
+func Step(at time.Time) uint64 {
+	return uint64(at.Unix() / int64(Period/time.Second)) //nolint:gosec // unix time is never negative
+}
+
+// Start returns the time step begins.
+func Start(step uint64) time.Time {
+	return time.Unix(int64(step)*int64(Period/time.Second), 0) //nolint:gosec // steps of real times fit into int64
+}
+
+// Code returns the code of the base32 encoded secret for time step.
+func Code(secret string, step uint64) (int, error) {
+	key, err := decode(secret)
+	if err != nil {
+		return 0, err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], step)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for range digits {
+		modulo *= 10
+	}
+
+	return int(value % modulo), nil
+}
+
+// decode accepts the secret as shown by authenticator setups: upper or lower case, with or without spaces and padding.
+func decode(secret string) ([]byte, error) {
+	normalized := strings.ToUpper(strings.TrimRight(strings.ReplaceAll(secret, " ", ""), "="))
+	if normalized == "" {
+		return nil, errors.New("empty totp secret")
+	}
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("decode totp secret: %w", err)
+	}
+
+	return key, nil
+}
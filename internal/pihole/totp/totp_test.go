@@ -0,0 +1,51 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to 6 digits
+	vectors := map[int64]int{
+		59:          287082,
+		1111111109:  81804,
+		1111111111:  50471,
+		1234567890:  5924,
+		2000000000:  279037,
+		20000000000: 353130,
+	}
+
+	for unix, expected := range vectors {
+		code, err := Code(rfcSecret, Step(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestCode_normalizesSecret(t *testing.T) {
+	step := Step(time.Unix(59, 0))
+
+	code, err := Code("gezd gnbv gy3t qojq gezd gnbv gy3t qojq", step)
+	require.NoError(t, err)
+	assert.Equal(t, 287082, code)
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(rfcSecret))
+	require.Error(t, Validate(""))
+	require.Error(t, Validate("not base32!"))
+}
+
+func TestStep(t *testing.T) {
+	assert.Equal(t, uint64(1), Step(time.Unix(59, 0)))
+	assert.Equal(t, uint64(2), Step(time.Unix(60, 0)))
+	assert.Equal(t, time.Unix(60, 0), Start(2))
+}
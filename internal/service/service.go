@@ -1,36 +1,69 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	gosync "sync"
+	"sync/atomic"
 
 	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	"github.com/lovelaze/nebula-sync/internal/api"
 	"github.com/lovelaze/nebula-sync/internal/config"
 	"github.com/lovelaze/nebula-sync/internal/pihole"
 	"github.com/lovelaze/nebula-sync/internal/sync"
+	"github.com/lovelaze/nebula-sync/internal/sync/checksum"
 	"github.com/lovelaze/nebula-sync/internal/sync/retry"
 	"github.com/lovelaze/nebula-sync/internal/webhook"
 	"github.com/lovelaze/nebula-sync/version"
 )
 
 type Service struct {
+	groups []*group
+	conf   config.Config
+	server *api.Server
+	// ctx is the context Run was called with, on demand syncs are cancelled with it
+	ctx context.Context
+}
+
+// group is a sync group with its own target, settings, callbacks and state.
+type group struct {
+	name      string
 	target    sync.Target
-	conf      config.Config
+	conf      *config.Sync
 	callbacks []sync.Callback
-	State     *sync.State
+	state     *sync.State
+	// running is held while the group syncs, a target only supports one sync at a time
+	running gosync.Mutex
+	// skipped and delayed count the scheduled runs that overlapped a running one
+	skipped atomic.Int64
+	delayed atomic.Int64
+	// pending holds the jobs with a delayed run waiting for the running one, at most one per job
+	pendingMu gosync.Mutex
+	pending   map[string]bool
 }
 
-func NewService(target sync.Target, conf config.Config, callbacks ...sync.Callback) *Service {
+func newGroup(name string, target sync.Target, conf *config.Sync, callbacks ...sync.Callback) *group {
 	state := sync.NewState()
 	cbs := append([]sync.Callback{state}, callbacks...)
 
-	return &Service{
+	return &group{
+		name:      name,
 		target:    target,
 		conf:      conf,
 		callbacks: cbs,
-		State:     state,
+		state:     state,
+		pending:   make(map[string]bool),
+	}
+}
+
+func NewService(target sync.Target, conf config.Config, callbacks ...sync.Callback) *Service {
+	return &Service{
+		groups: []*group{newGroup(config.DefaultGroup, target, conf.Sync, callbacks...)},
+		conf:   conf,
 	}
 }
 
@@ -43,63 +76,188 @@ func Init() (*Service, error) {
 	httpClient := conf.Client.NewHTTPClient()
 	retry.Init(conf.Client)
 
-	primary := pihole.NewClient(conf.Primary, httpClient)
-	var replicas []pihole.Client
-	for _, replica := range conf.Replicas {
-		replicas = append(replicas, pihole.NewClient(replica, httpClient))
-	}
+	service := &Service{conf: conf}
+	// groups share the store of their state file, several of them may use the same one
+	stores := make(map[string]*checksum.Store)
+	for _, syncGroup := range conf.SyncGroups() {
+		primary := pihole.NewClient(syncGroup.Primary, httpClient)
+		var replicas []pihole.Client
+		for _, replica := range syncGroup.Replicas {
+			replicas = append(replicas, pihole.NewClient(replica, httpClient))
+		}
 
-	webhookClient := webhook.NewClient(conf.Sync.WebhookSettings)
+		webhookClient := webhook.NewClient(syncGroup.Sync.WebhookSettings)
 
-	target := sync.NewTarget(primary, replicas)
-	service := NewService(target, conf, webhookClient)
+		checksums, err := groupStore(stores, syncGroup, len(conf.Groups) > 0)
+		if err != nil {
+			return nil, fmt.Errorf("sync group %s: %w", syncGroup.Name, err)
+		}
+
+		target := sync.NewTarget(primary, replicas, syncGroup.Sync.Parallelism, checksums)
+		service.groups = append(service.groups, newGroup(syncGroup.Name, target, syncGroup.Sync, webhookClient))
+	}
 
-	if conf.API.Enabled && conf.Sync.Cron != nil {
-		server := api.NewServer(service.State)
-		server.Start()
+	if conf.API.Enabled && service.scheduled() {
+		service.server = api.NewServer(service.states(), service, conf.API.Token)
 	}
 
 	return service, nil
 }
 
-func (service *Service) Run() error {
+// groupStore returns the checksum store of syncGroup, scoped to the group if sync groups are configured.
+func groupStore(stores map[string]*checksum.Store, syncGroup *config.Group, grouped bool) (*checksum.Store, error) {
+	store, exists := stores[syncGroup.Sync.StateFile]
+	if !exists {
+		var err error
+		if store, err = checksum.NewStore(syncGroup.Sync.StateFile); err != nil {
+			return nil, err
+		}
+		stores[syncGroup.Sync.StateFile] = store
+	}
+
+	if grouped {
+		return store.Group(syncGroup.Name), nil
+	}
+	return store, nil
+}
+
+// Run syncs every group once and then on its cron schedules until ctx is done.
+func (service *Service) Run(ctx context.Context) error {
+	service.ctx = ctx
 	log.Info().Msgf("Starting nebula-sync %s", version.Version)
 	log.Debug().Str("config", service.conf.String()).Msgf("Settings")
+	defer service.closeSessions(ctx)
 
-	if err := service.sync(service.target); err != nil {
+	if service.server != nil {
+		service.server.Start()
+	}
+
+	// a failing group does not keep the others from syncing
+	var errs []error
+	for _, group := range service.groups {
+		if err := service.sync(ctx, group); err != nil {
+			errs = append(errs, fmt.Errorf("sync group %s: %w", group.name, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
 		return err
 	}
 
-	if service.conf.Sync.Cron != nil {
-		return service.startCron(func() {
-			if err := service.sync(service.target); err != nil {
-				log.Error().Err(err).Msg("Sync failed")
-			}
-		})
+	if service.scheduled() {
+		return service.startCron(ctx)
 	}
 
 	return nil
 }
 
-func (service *Service) sync(t sync.Target) error {
-	var err error
-	if service.conf.Sync.FullSync {
-		err = t.FullSync(service.conf.Sync)
-	} else {
-		err = t.SelectiveSync(service.conf.Sync)
+func (service *Service) Plan(ctx context.Context) error {
+	log.Info().Msgf("Planning nebula-sync %s", version.Version)
+	log.Debug().Str("config", service.conf.String()).Msgf("Settings")
+	defer service.closeSessions(ctx)
+
+	var errs []error
+	for _, group := range service.groups {
+		if err := service.plan(ctx, group, group.conf); err != nil {
+			errs = append(errs, fmt.Errorf("sync group %s: %w", group.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (service *Service) sync(ctx context.Context, group *group) error {
+	group.running.Lock()
+	defer group.running.Unlock()
+
+	return service.syncWith(ctx, group, group.conf)
+}
+
+// syncWith syncs group with conf instead of the group's own settings. The caller must hold group.running.
+func (service *Service) syncWith(ctx context.Context, group *group, conf *config.Sync) error {
+	err := withTimeout(ctx, conf.Timeout, func(ctx context.Context) error {
+		return service.run(ctx, group, conf)
+	})
+	group.notifyVersions()
+
+	// a detect run reports drift through its own callbacks, a dry run changes nothing, neither is reported as a sync
+	if conf.Mode == config.ModeDetect || conf.DryRun {
+		return err
 	}
 
-	service.runCallbacks(err)
+	group.runCallbacks(err)
 
 	if err == nil {
-		log.Info().Msg("Sync completed")
+		group.logger().Info().Msg("Sync completed")
 	}
 
 	return err
 }
 
-func (service *Service) runCallbacks(syncError error) {
-	for _, callback := range service.callbacks {
+func (service *Service) run(ctx context.Context, group *group, conf *config.Sync) error {
+	if conf.Mode == config.ModeDetect {
+		return service.detect(ctx, group, conf)
+	}
+
+	if conf.DryRun {
+		return service.plan(ctx, group, conf)
+	}
+
+	if conf.FullSync {
+		return group.target.FullSync(ctx, conf)
+	}
+	return group.target.SelectiveSync(ctx, conf)
+}
+
+func (service *Service) plan(ctx context.Context, group *group, conf *config.Sync) error {
+	plan, err := group.target.Plan(ctx, conf)
+	if err != nil {
+		return err
+	}
+
+	plan.Log()
+	group.logger().Info().Msg("Dry run completed, no changes were made")
+
+	return nil
+}
+
+func (service *Service) detect(ctx context.Context, group *group, conf *config.Sync) error {
+	drift, err := group.target.Detect(ctx, conf)
+	if drift != nil {
+		for _, callback := range group.callbacks {
+			if driftCallback, ok := callback.(sync.DriftCallback); ok {
+				driftCallback.OnDrift(drift)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if drift.Detected() {
+		group.logger().Warn().Msg("Drift detection completed, replicas drifted from the primary")
+	} else {
+		group.logger().Info().Msg("Drift detection completed, no drift found")
+	}
+
+	return nil
+}
+
+// closeSessions invalidates the sessions groups kept between runs, after waiting for running syncs to finish.
+// Sessions are invalidated even if ctx is done, so they do not take up API seats after shutdown.
+func (service *Service) closeSessions(ctx context.Context) {
+	for _, group := range service.groups {
+		if !group.conf.KeepSessions {
+			continue
+		}
+
+		group.running.Lock()
+		group.target.Close(context.WithoutCancel(ctx))
+		group.running.Unlock()
+	}
+}
+
+func (group *group) runCallbacks(syncError error) {
+	for _, callback := range group.callbacks {
 		if syncError != nil {
 			callback.OnFailure(syncError)
 		} else {
@@ -108,13 +266,131 @@ func (service *Service) runCallbacks(syncError error) {
 	}
 }
 
-func (service *Service) startCron(cmd func()) error {
+// notifyVersions passes the versions detected by the latest run of the group's target to the callbacks interested in them.
+func (group *group) notifyVersions() {
+	reporter, ok := group.target.(sync.VersionReporter)
+	if !ok {
+		return
+	}
+
+	versions := reporter.Versions()
+	if versions == nil {
+		return
+	}
+
+	for _, callback := range group.callbacks {
+		if versionCallback, ok := callback.(sync.VersionCallback); ok {
+			versionCallback.OnVersions(versions)
+		}
+	}
+}
+
+func (group *group) logger() *zerolog.Logger {
+	logger := log.With().Str("group", group.name).Logger()
+	return &logger
+}
+
+// scheduled reports whether at least one group syncs on a cron schedule.
+func (service *Service) scheduled() bool {
+	for _, group := range service.groups {
+		if group.conf.Cron != nil || group.conf.GravityCron != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (service *Service) states() map[string]*sync.State {
+	states := make(map[string]*sync.State, len(service.groups))
+	for _, group := range service.groups {
+		states[group.name] = group.state
+	}
+	return states
+}
+
+// startCron runs the cron schedules of every group until ctx is done, then waits for running jobs to finish.
+func (service *Service) startCron(ctx context.Context) error {
 	cron := cron.New()
 
-	if _, err := cron.AddFunc(*service.conf.Sync.Cron, cmd); err != nil {
-		return fmt.Errorf("cron job: %w", err)
+	for _, group := range service.groups {
+		if group.conf.Cron != nil {
+			if _, err := cron.AddFunc(*group.conf.Cron, func() {
+				service.runScheduled(group, "sync", func() error {
+					return service.syncWith(ctx, group, group.conf)
+				})
+			}); err != nil {
+				return fmt.Errorf("cron job for group %s: %w", group.name, err)
+			}
+		}
+
+		if group.conf.GravityCron != nil {
+			if group.conf.Mode == config.ModeDetect || group.conf.DryRun {
+				group.logger().Warn().Msg("Gravity cron is ignored in detect and dry run mode")
+				continue
+			}
+
+			if _, err := cron.AddFunc(*group.conf.GravityCron, func() {
+				service.runScheduled(group, "gravity refresh", func() error {
+					return withTimeout(ctx, group.conf.Timeout, func(ctx context.Context) error {
+						return group.target.RunGravity(ctx, group.conf)
+					})
+				})
+			}); err != nil {
+				return fmt.Errorf("gravity cron job for group %s: %w", group.name, err)
+			}
+		}
 	}
 
-	cron.Run()
+	cron.Start()
+	<-ctx.Done()
+
+	log.Info().Msg("Stopping scheduled syncs")
+	<-cron.Stop().Done()
 	return nil
 }
+
+// runScheduled runs the job of a cron schedule of group. A job that fires while the group is still syncing is skipped
+// or, with SYNC_OVERLAP=delay, started once the running one has finished. Only one run of a job is delayed, further
+// ones are skipped, so a job slower than its schedule does not pile up runs.
+func (service *Service) runScheduled(group *group, job string, run func() error) {
+	if !group.running.TryLock() {
+		if group.conf.Overlap != config.OverlapDelay || !group.setPending(job) {
+			group.logger().Warn().
+				Str("job", job).
+				Int64("skipped", group.skipped.Add(1)).
+				Msg("Previous run still in progress, skipping scheduled run")
+			return
+		}
+
+		group.logger().Warn().
+			Str("job", job).
+			Int64("delayed", group.delayed.Add(1)).
+			Msg("Previous run still in progress, delaying scheduled run")
+		group.running.Lock()
+		group.clearPending(job)
+	}
+	defer group.running.Unlock()
+
+	if err := run(); err != nil {
+		group.logger().Error().Err(err).Msgf("Scheduled %s failed", job)
+	}
+}
+
+// setPending marks a delayed run of job as pending, false if one already is.
+func (group *group) setPending(job string) bool {
+	group.pendingMu.Lock()
+	defer group.pendingMu.Unlock()
+
+	if group.pending[job] {
+		return false
+	}
+	group.pending[job] = true
+	return true
+}
+
+func (group *group) clearPending(job string) {
+	group.pendingMu.Lock()
+	defer group.pendingMu.Unlock()
+
+	delete(group.pending, job)
+}
@@ -1,14 +1,22 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/lovelaze/nebula-sync/internal/config"
 	syncmock "github.com/lovelaze/nebula-sync/internal/mocks/sync"
 	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/sync"
+	"github.com/lovelaze/nebula-sync/internal/sync/checksum"
 )
 
 func TestRun_full(t *testing.T) {
@@ -23,15 +31,15 @@ func TestRun_full(t *testing.T) {
 
 	target := syncmock.NewTarget(t)
 	callback := syncmock.NewCallback(t)
-	target.On("FullSync", conf.Sync).Return(nil)
+	target.On("FullSync", mock.Anything, conf.Sync).Return(nil)
 	callback.On("OnSuccess").Return(nil)
 
 	service := NewService(target, conf, callback)
 
-	err := service.Run()
+	err := service.Run(context.Background())
 	require.NoError(t, err)
 
-	target.AssertCalled(t, "FullSync", conf.Sync)
+	target.AssertCalled(t, "FullSync", mock.Anything, conf.Sync)
 }
 
 func TestRun_selective(t *testing.T) {
@@ -46,15 +54,15 @@ func TestRun_selective(t *testing.T) {
 
 	target := syncmock.NewTarget(t)
 	callback := syncmock.NewCallback(t)
-	target.On("SelectiveSync", conf.Sync).Return(nil)
+	target.On("SelectiveSync", mock.Anything, conf.Sync).Return(nil)
 	callback.On("OnSuccess").Return(nil)
 
 	service := NewService(target, conf, callback)
 
-	err := service.Run()
+	err := service.Run(context.Background())
 	require.NoError(t, err)
 
-	target.AssertCalled(t, "SelectiveSync", conf.Sync)
+	target.AssertCalled(t, "SelectiveSync", mock.Anything, conf.Sync)
 }
 
 func TestRun_webhook_success(t *testing.T) {
@@ -70,15 +78,15 @@ func TestRun_webhook_success(t *testing.T) {
 	target := syncmock.NewTarget(t)
 	callback := syncmock.NewCallback(t)
 
-	target.On("SelectiveSync", conf.Sync).Return(nil)
+	target.On("SelectiveSync", mock.Anything, conf.Sync).Return(nil)
 	callback.On("OnSuccess").Return(nil)
 
 	service := NewService(target, conf, callback)
 
-	err := service.Run()
+	err := service.Run(context.Background())
 	require.NoError(t, err)
 
-	target.AssertCalled(t, "SelectiveSync", conf.Sync)
+	target.AssertCalled(t, "SelectiveSync", mock.Anything, conf.Sync)
 	callback.AssertCalled(t, "OnSuccess")
 	callback.AssertNotCalled(t, "OnFailure")
 }
@@ -97,15 +105,15 @@ func TestRun_webhook_failure(t *testing.T) {
 	target := syncmock.NewTarget(t)
 	callback := syncmock.NewCallback(t)
 
-	target.On("SelectiveSync", conf.Sync).Return(syncErr)
+	target.On("SelectiveSync", mock.Anything, conf.Sync).Return(syncErr)
 	callback.On("OnFailure", syncErr).Return(nil)
 
 	service := NewService(target, conf, callback)
 
-	err := service.Run()
+	err := service.Run(context.Background())
 	require.ErrorIs(t, err, syncErr)
 
-	target.AssertCalled(t, "SelectiveSync", conf.Sync)
+	target.AssertCalled(t, "SelectiveSync", mock.Anything, conf.Sync)
 	callback.AssertCalled(t, "OnFailure", syncErr)
 	callback.AssertNotCalled(t, "OnSuccess")
 }
@@ -123,14 +131,229 @@ func TestRun_webhook_error_does_not_affect_result(t *testing.T) {
 	target := syncmock.NewTarget(t)
 	callback := syncmock.NewCallback(t)
 
-	target.On("FullSync", conf.Sync).Return(nil)
+	target.On("FullSync", mock.Anything, conf.Sync).Return(nil)
 	callback.On("OnSuccess").Return(nil)
 
 	service := NewService(target, conf, callback)
 
-	err := service.Run()
+	err := service.Run(context.Background())
 	require.NoError(t, err)
 
-	target.AssertCalled(t, "FullSync", conf.Sync)
+	target.AssertCalled(t, "FullSync", mock.Anything, conf.Sync)
 	callback.AssertCalled(t, "OnSuccess")
 }
+
+func TestRun_dryRun(t *testing.T) {
+	conf := config.Config{
+		Primary:  model.PiHole{},
+		Replicas: []model.PiHole{},
+		Sync: &config.Sync{
+			FullSync: true,
+			DryRun:   true,
+			Cron:     nil,
+		},
+	}
+
+	target := syncmock.NewTarget(t)
+	callback := syncmock.NewCallback(t)
+
+	target.On("Plan", mock.Anything, conf.Sync).Return(&sync.Plan{}, nil)
+
+	service := NewService(target, conf, callback)
+
+	err := service.Run(context.Background())
+	require.NoError(t, err)
+
+	target.AssertCalled(t, "Plan", mock.Anything, conf.Sync)
+	target.AssertNotCalled(t, "FullSync", mock.Anything, conf.Sync)
+	callback.AssertNotCalled(t, "OnSuccess")
+}
+
+func TestRun_groups(t *testing.T) {
+	homeConf := &config.Sync{FullSync: true}
+	officeConf := &config.Sync{FullSync: false}
+
+	syncErr := errors.New("sync failed")
+	home := syncmock.NewTarget(t)
+	office := syncmock.NewTarget(t)
+
+	home.On("FullSync", mock.Anything, homeConf).Return(syncErr)
+	office.On("SelectiveSync", mock.Anything, officeConf).Return(nil)
+
+	service := &Service{groups: []*group{
+		newGroup("home", home, homeConf),
+		newGroup("office", office, officeConf),
+	}}
+
+	err := service.Run(context.Background())
+	require.ErrorIs(t, err, syncErr)
+	require.ErrorContains(t, err, "sync group home")
+
+	office.AssertCalled(t, "SelectiveSync", mock.Anything, officeConf)
+	require.True(t, service.groups[1].state.Outcomes()[0].Success)
+	require.False(t, service.groups[0].state.Outcomes()[0].Success)
+}
+
+func TestGroupStore_sharedStateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	stores := make(map[string]*checksum.Store)
+
+	home, err := groupStore(stores, &config.Group{Name: "home", Sync: &config.Sync{StateFile: path}}, true)
+	require.NoError(t, err)
+	office, err := groupStore(stores, &config.Group{Name: "office", Sync: &config.Sync{StateFile: path}}, true)
+	require.NoError(t, err)
+
+	require.NoError(t, home.Set("http://replica", checksum.KindConfig, "home"))
+	require.NoError(t, office.Set("http://replica", checksum.KindConfig, "office"))
+
+	reloaded, err := checksum.NewStore(path)
+	require.NoError(t, err)
+	require.True(t, reloaded.Group("home").Matches("http://replica", checksum.KindConfig, "home"))
+	require.True(t, reloaded.Group("office").Matches("http://replica", checksum.KindConfig, "office"))
+}
+
+func TestRun_detect(t *testing.T) {
+	conf := config.Config{
+		Sync: &config.Sync{
+			FullSync: true,
+			Mode:     config.ModeDetect,
+		},
+	}
+
+	drift := &sync.Drift{Replicas: []sync.ReplicaDrift{{Replica: "http://replica", Gravity: []string{"adlist"}}}}
+
+	target := syncmock.NewTarget(t)
+	callback := syncmock.NewCallback(t)
+	driftCallback := syncmock.NewDriftCallback(t)
+	target.On("Detect", mock.Anything, conf.Sync).Return(drift, nil)
+	driftCallback.On("OnDrift", drift).Return(nil)
+
+	service := NewService(target, conf, &driftNotifier{Callback: callback, DriftCallback: driftCallback})
+
+	err := service.Run(context.Background())
+	require.NoError(t, err)
+
+	target.AssertNotCalled(t, "FullSync", mock.Anything, conf.Sync)
+	callback.AssertNotCalled(t, "OnSuccess")
+	require.Equal(t, drift, service.groups[0].state.Drift())
+}
+
+type driftNotifier struct {
+	sync.Callback
+	sync.DriftCallback
+}
+
+func TestRun_versions(t *testing.T) {
+	conf := config.Config{
+		Sync: &config.Sync{
+			FullSync: true,
+		},
+	}
+
+	versions := &sync.Versions{Primary: sync.TargetVersion{Target: "http://primary", FTL: "v6.0.4"}}
+
+	target := syncmock.NewTarget(t)
+	reporter := syncmock.NewVersionReporter(t)
+	target.On("FullSync", mock.Anything, conf.Sync).Return(nil)
+	reporter.On("Versions").Return(versions)
+
+	service := NewService(&versionTarget{Target: target, VersionReporter: reporter}, conf)
+
+	require.NoError(t, service.Run(context.Background()))
+	assert.Equal(t, versions, service.groups[0].state.Versions())
+}
+
+type versionTarget struct {
+	sync.Target
+	sync.VersionReporter
+}
+
+func TestRunScheduled_skip(t *testing.T) {
+	g := newGroup(config.DefaultGroup, syncmock.NewTarget(t), &config.Sync{Overlap: config.OverlapSkip})
+	service := &Service{groups: []*group{g}}
+
+	g.running.Lock()
+	defer g.running.Unlock()
+
+	service.runScheduled(g, "sync", func() error {
+		t.Fatal("overlapping run was not skipped")
+		return nil
+	})
+
+	assert.Equal(t, int64(1), g.skipped.Load())
+}
+
+func TestRunScheduled_delay(t *testing.T) {
+	g := newGroup(config.DefaultGroup, syncmock.NewTarget(t), &config.Sync{Overlap: config.OverlapDelay})
+	service := &Service{groups: []*group{g}}
+
+	g.running.Lock()
+
+	ran := make(chan struct{})
+	go service.runScheduled(g, "sync", func() error {
+		close(ran)
+		return nil
+	})
+
+	require.Eventually(t, func() bool { return g.delayed.Load() == 1 }, time.Second, 10*time.Millisecond)
+	select {
+	case <-ran:
+		t.Fatal("overlapping run was not delayed")
+	default:
+	}
+
+	g.running.Unlock()
+	<-ran
+}
+
+func TestRunScheduled_delayCoalesces(t *testing.T) {
+	g := newGroup(config.DefaultGroup, syncmock.NewTarget(t), &config.Sync{Overlap: config.OverlapDelay})
+	service := &Service{groups: []*group{g}}
+
+	g.running.Lock()
+
+	var runs atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		service.runScheduled(g, "sync", func() error {
+			runs.Add(1)
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool { return g.delayed.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// further runs of the job are skipped while one is pending, other jobs are still delayed
+	for range 3 {
+		service.runScheduled(g, "sync", func() error {
+			t.Fatal("second pending run was not skipped")
+			return nil
+		})
+	}
+	assert.Equal(t, int64(3), g.skipped.Load())
+
+	go service.runScheduled(g, "gravity refresh", func() error { return nil })
+	require.Eventually(t, func() bool { return g.delayed.Load() == 2 }, time.Second, 10*time.Millisecond)
+
+	g.running.Unlock()
+	<-done
+	assert.Equal(t, int64(1), runs.Load())
+}
+
+func TestRun_keepSessions(t *testing.T) {
+	conf := config.Config{
+		Sync: &config.Sync{
+			FullSync:     true,
+			KeepSessions: true,
+		},
+	}
+
+	target := syncmock.NewTarget(t)
+	target.On("FullSync", mock.Anything, conf.Sync).Return(nil)
+	target.On("Close", mock.Anything).Return()
+
+	service := NewService(target, conf)
+
+	require.NoError(t, service.Run(context.Background()))
+	target.AssertCalled(t, "Close", mock.Anything)
+}
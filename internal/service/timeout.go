@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is returned when a run did not complete within SYNC_TIMEOUT.
+var ErrTimeout = errors.New("sync timed out")
+
+// withTimeout runs fn with a context that is cancelled once timeout has passed, a zero timeout never cancels.
+func withTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s: %w", ErrTimeout, timeout, err)
+	}
+
+	return err
+}
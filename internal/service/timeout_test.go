@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTimeout(t *testing.T) {
+	start := time.Now()
+	err := withTimeout(context.Background(), 50*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	require.ErrorIs(t, err, ErrTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestWithTimeout_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := withTimeout(ctx, time.Minute, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotErrorIs(t, err, ErrTimeout)
+}
+
+func TestWithTimeout_disabled(t *testing.T) {
+	failure := errors.New("failure")
+
+	err := withTimeout(context.Background(), 0, func(ctx context.Context) error {
+		_, hasDeadline := ctx.Deadline()
+		require.False(t, hasDeadline)
+		return failure
+	})
+
+	require.ErrorIs(t, err, failure)
+	require.NotErrorIs(t, err, ErrTimeout)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/lovelaze/nebula-sync/internal/api"
+	"github.com/lovelaze/nebula-sync/internal/config"
+)
+
+// TriggerSync starts an on demand sync of a group in the background, unless the group is already syncing.
+func (service *Service) TriggerSync(request api.SyncRequest) (*api.SyncResponse, error) {
+	group, err := service.group(request.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	conf, err := requestedConf(group.conf, request)
+	if err != nil {
+		return nil, &api.InvalidRequestError{Reason: err.Error()}
+	}
+
+	if !group.running.TryLock() {
+		group.logger().Warn().Msg("Sync requested through the API while a sync is in progress")
+		return nil, api.ErrSyncInProgress
+	}
+
+	id, err := newRunID()
+	if err != nil {
+		group.running.Unlock()
+		return nil, err
+	}
+
+	logger := group.logger().With().Str("run", id).Logger()
+	logger.Info().
+		Str("mode", request.Mode).
+		Strs("config", request.Config).
+		Strs("gravity", request.Gravity).
+		Msg("Sync requested through the API")
+
+	go func() {
+		defer group.running.Unlock()
+
+		if err := service.syncWith(service.context(), group, conf); err != nil {
+			logger.Error().Err(err).Msg("Sync failed")
+		}
+	}()
+
+	return &api.SyncResponse{ID: id, Group: group.name}, nil
+}
+
+// context returns the context of Run, the background context if the service is not running.
+func (service *Service) context() context.Context {
+	if service.ctx == nil {
+		return context.Background()
+	}
+	return service.ctx
+}
+
+// group returns the group called name, or the only group if name is empty.
+func (service *Service) group(name string) (*group, error) {
+	if name == "" {
+		if len(service.groups) != 1 {
+			return nil, &api.InvalidRequestError{Reason: "group is required with more than one sync group"}
+		}
+		return service.groups[0], nil
+	}
+
+	for _, group := range service.groups {
+		if group.name == name {
+			return group, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", api.ErrUnknownGroup, name)
+}
+
+// requestedConf returns a copy of conf with the mode and sections of request applied.
+func requestedConf(conf *config.Sync, request api.SyncRequest) (*config.Sync, error) {
+	requested := *conf
+
+	switch request.Mode {
+	case "":
+	case "full":
+		requested.FullSync = true
+	case "selective":
+		requested.FullSync = false
+	default:
+		return nil, fmt.Errorf("unknown mode %q, must be full or selective", request.Mode)
+	}
+
+	if requested.FullSync && (request.Config != nil || request.Gravity != nil) {
+		return nil, errors.New("config and gravity sections can only be selected for a selective sync")
+	}
+
+	if request.Config != nil {
+		configSettings, err := conf.ConfigSettings.Select(request.Config)
+		if err != nil {
+			return nil, err
+		}
+		requested.ConfigSettings = configSettings
+	}
+
+	if request.Gravity != nil {
+		gravitySettings, err := config.SelectGravity(request.Gravity)
+		if err != nil {
+			return nil, err
+		}
+		requested.GravitySettings = gravitySettings
+	}
+
+	return &requested, nil
+}
+
+func newRunID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("generate run id: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
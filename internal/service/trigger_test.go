@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/api"
+	"github.com/lovelaze/nebula-sync/internal/config"
+	syncmock "github.com/lovelaze/nebula-sync/internal/mocks/sync"
+)
+
+func TestTriggerSync(t *testing.T) {
+	conf := &config.Sync{
+		FullSync:       true,
+		ConfigSettings: &config.ConfigSettings{DNS: config.NewConfigSetting(false, []string{"upstreams"}, nil)},
+	}
+
+	target := syncmock.NewTarget(t)
+	done := make(chan *config.Sync, 1)
+	target.On("SelectiveSync", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		done <- args.Get(1).(*config.Sync)
+	})
+
+	service := &Service{groups: []*group{newGroup(config.DefaultGroup, target, conf)}}
+
+	response, err := service.TriggerSync(api.SyncRequest{Mode: "selective", Config: []string{"dns"}, Gravity: []string{"adlist"}})
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultGroup, response.Group)
+	assert.Len(t, response.ID, 16)
+
+	synced := <-done
+	assert.False(t, synced.FullSync)
+	assert.True(t, synced.ConfigSettings.DNS.Enabled)
+	assert.Equal(t, []string{"upstreams"}, synced.ConfigSettings.DNS.Filter.Keys)
+	assert.False(t, synced.ConfigSettings.DHCP.Enabled)
+	assert.Equal(t, &config.GravitySettings{Adlist: true}, synced.GravitySettings)
+	assert.True(t, conf.FullSync)
+	assert.False(t, conf.ConfigSettings.DNS.Enabled)
+}
+
+func TestTriggerSync_inProgress(t *testing.T) {
+	target := syncmock.NewTarget(t)
+	service := &Service{groups: []*group{newGroup(config.DefaultGroup, target, &config.Sync{})}}
+
+	service.groups[0].running.Lock()
+	defer service.groups[0].running.Unlock()
+
+	_, err := service.TriggerSync(api.SyncRequest{})
+	require.ErrorIs(t, err, api.ErrSyncInProgress)
+}
+
+func TestTriggerSync_invalid(t *testing.T) {
+	service := &Service{groups: []*group{
+		newGroup("home", syncmock.NewTarget(t), &config.Sync{}),
+		newGroup("office", syncmock.NewTarget(t), &config.Sync{}),
+	}}
+
+	var invalidRequestError *api.InvalidRequestError
+
+	_, err := service.TriggerSync(api.SyncRequest{})
+	require.ErrorAs(t, err, &invalidRequestError)
+
+	_, err = service.TriggerSync(api.SyncRequest{Group: "garage"})
+	require.ErrorIs(t, err, api.ErrUnknownGroup)
+
+	_, err = service.TriggerSync(api.SyncRequest{Group: "home", Mode: "partial"})
+	require.ErrorAs(t, err, &invalidRequestError)
+
+	_, err = service.TriggerSync(api.SyncRequest{Group: "home", Mode: "full", Config: []string{"dns"}})
+	require.ErrorAs(t, err, &invalidRequestError)
+
+	_, err = service.TriggerSync(api.SyncRequest{Group: "home", Mode: "selective", Config: []string{"nope"}})
+	require.ErrorAs(t, err, &invalidRequestError)
+}
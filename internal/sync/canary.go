@@ -0,0 +1,108 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+)
+
+const dnsQueryTimeout = 5 * time.Second
+
+// dnsPort is the port DNS test queries are sent to on the canary.
+var dnsPort = "53"
+
+// rollout runs syncFunc against all replicas. With a canary rollout the canary is synced and verified first,
+// and the remaining replicas are only synced when verification succeeds.
+func (target *target) rollout(ctx context.Context, conf *config.Sync, syncFunc func() error) error {
+	if !conf.Canary || len(target.Replicas) < 2 {
+		return syncFunc()
+	}
+
+	canary, err := target.canary(conf.CanaryReplica)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("replica", canary.String()).Msg("Syncing canary...")
+	target.run.replicas = []pihole.Client{canary}
+	if err := syncFunc(); err != nil {
+		return fmt.Errorf("canary %s: %w", canary.String(), err)
+	}
+	if err, failed := target.run.failures[canary]; failed {
+		return fmt.Errorf("canary %s: %w", canary.String(), err)
+	}
+
+	if err := target.verifyCanary(ctx, canary, conf.CanaryDNSQuery); err != nil {
+		log.Error().Str("replica", canary.String()).Err(err).Msg("Canary verification failed, stopping rollout")
+		return fmt.Errorf("canary %s verification: %w", canary.String(), err)
+	}
+	log.Info().Str("replica", canary.String()).Msg("Canary verified, syncing remaining replicas...")
+
+	target.run.replicas = slices.DeleteFunc(slices.Clone(target.Replicas), func(replica pihole.Client) bool {
+		return replica == canary
+	})
+	return syncFunc()
+}
+
+// canary returns the replica matching name, or the first replica when name is empty.
+func (target *target) canary(name string) (pihole.Client, error) {
+	if name == "" {
+		return target.Replicas[0], nil
+	}
+
+	for _, replica := range target.Replicas {
+		if strings.TrimSuffix(replica.String(), "/") == strings.TrimSuffix(name, "/") {
+			return replica, nil
+		}
+	}
+
+	return nil, fmt.Errorf("canary replica %s not found", name)
+}
+
+// verifyCanary checks that the canary API is reachable, its config matches the primary and optionally that it resolves query.
+func (target *target) verifyCanary(ctx context.Context, canary pihole.Client, query string) error {
+	if err := target.verifyConfig(ctx, canary, target.run.desiredConfig(canary)); err != nil {
+		return err
+	}
+
+	if query == "" {
+		return nil
+	}
+
+	return resolve(ctx, canary, query)
+}
+
+// resolve sends a DNS query for name to the host of replica.
+func resolve(ctx context.Context, replica pihole.Client, name string) error {
+	replicaURL, err := url.Parse(replica.String())
+	if err != nil {
+		return fmt.Errorf("parse replica url: %w", err)
+	}
+
+	server := net.JoinHostPort(replicaURL.Hostname(), dnsPort)
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer := net.Dialer{}
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dnsQueryTimeout)
+	defer cancel()
+
+	if _, err := resolver.LookupHost(ctx, name); err != nil {
+		return fmt.Errorf("dns query %s: %w", name, err)
+	}
+
+	return nil
+}
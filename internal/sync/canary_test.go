@@ -0,0 +1,129 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+)
+
+func TestTarget_SelectiveSync_canary(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+	canary := piholemock.NewClient(t)
+
+	target := NewTarget(primary, []pihole.Client{replica, canary}, 1, nil)
+
+	configResponse := emptyConfigResponse()
+	configResponse.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}}
+
+	configSettings := disabledConfigSettings()
+	configSettings.DNS = config.NewConfigSetting(true, nil, nil)
+
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	canary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	replica.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	canary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+
+	primary.EXPECT().GetTeleporter(mock.Anything).Twice().Return([]byte{}, nil)
+	primary.EXPECT().GetConfig(mock.Anything).Twice().Return(configResponse, nil)
+
+	canaryImport := canary.EXPECT().PostTeleporter(mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+	canary.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	canary.EXPECT().PatchConfig(mock.Anything, mock.Anything).Once().Return(nil)
+	canary.EXPECT().GetConfig(mock.Anything).Once().Return(configResponse, nil)
+
+	replica.EXPECT().PostTeleporter(mock.Anything, mock.Anything, mock.Anything).Once().Return(nil).NotBefore(canaryImport)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	replica.EXPECT().PatchConfig(mock.Anything, mock.Anything).Once().Return(nil)
+
+	primary.EXPECT().HasSession().Return(true)
+
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	replica.EXPECT().HasSession().Return(true)
+	replica.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	canary.EXPECT().HasSession().Return(true)
+	canary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+
+	replica.EXPECT().String().Return("http://replica")
+	canary.EXPECT().String().Return("http://canary")
+
+	err := target.SelectiveSync(context.Background(), &config.Sync{
+		Canary:          true,
+		CanaryReplica:   "http://canary",
+		GravitySettings: &config.GravitySettings{},
+		ConfigSettings:  configSettings,
+	})
+
+	require.NoError(t, err)
+}
+
+func TestTarget_SelectiveSync_canaryVerificationFailed(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	canary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	target := NewTarget(primary, []pihole.Client{canary, replica}, 1, nil)
+
+	configResponse := emptyConfigResponse()
+	configResponse.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}}
+
+	configSettings := disabledConfigSettings()
+	configSettings.DNS = config.NewConfigSetting(true, nil, nil)
+
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	canary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	canary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	replica.EXPECT().String().Return("http://replica")
+	replica.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+
+	primary.EXPECT().GetTeleporter(mock.Anything).Once().Return([]byte{}, nil)
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(configResponse, nil)
+
+	canary.EXPECT().PostTeleporter(mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+	canary.EXPECT().PatchConfig(mock.Anything, mock.Anything).Once().Return(nil)
+	// the canary rejects the patch and keeps its old config
+	canary.EXPECT().GetConfig(mock.Anything).Twice().Return(emptyConfigResponse(), nil)
+
+	primary.EXPECT().HasSession().Return(true)
+
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	canary.EXPECT().HasSession().Return(true)
+	canary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	replica.EXPECT().HasSession().Return(true)
+	replica.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+
+	canary.EXPECT().String().Return("http://canary")
+
+	err := target.SelectiveSync(context.Background(), &config.Sync{
+		Canary:          true,
+		GravitySettings: &config.GravitySettings{},
+		ConfigSettings:  configSettings,
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "canary http://canary verification: replica http://canary did not converge: dns.upstreams")
+	replica.AssertNotCalled(t, "PostTeleporter", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_target_canary_notFound(t *testing.T) {
+	replica := piholemock.NewClient(t)
+	replica.EXPECT().String().Return("http://replica")
+
+	target := target{Replicas: []pihole.Client{replica}}
+
+	_, err := target.canary("http://other")
+	require.EqualError(t, err, "canary replica http://other not found")
+}
@@ -0,0 +1,114 @@
+package checksum
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+)
+
+// volatileEntries are teleporter archive entries that are never imported and change on every export.
+var volatileEntries = []string{
+	"etc/pihole/pihole.toml",
+}
+
+// gravityEntry is the teleporter archive entry, by file name, holding the gravity database. It changes whenever
+// gravity runs, even if no entry was changed.
+const gravityEntry = "gravity.db"
+
+// Teleporter returns a checksum of the teleporter archive contents and the import settings.
+// Archive metadata such as modification times is ignored so two exports of an unchanged Pi-hole match. The gravity
+// database is left out in favour of gravity, the checksum of the gravity entries read through the API.
+func Teleporter(payload []byte, gravity string, request any) (string, error) {
+	hash := sha256.New()
+
+	settings, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("marshal teleporter request: %w", err)
+	}
+	hash.Write(settings)
+	hash.Write([]byte(gravity))
+
+	reader, err := zip.NewReader(bytes.NewReader(payload), int64(len(payload)))
+	if err != nil {
+		// not an archive, fall back to the raw bytes
+		hash.Write(payload)
+		return hex.EncodeToString(hash.Sum(nil)), nil //nolint:nilerr // raw payload is hashed instead
+	}
+
+	files := slices.Clone(reader.File)
+	slices.SortFunc(files, func(a, b *zip.File) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, file := range files {
+		if slices.Contains(volatileEntries, file.Name) || path.Base(file.Name) == gravityEntry || file.FileInfo().IsDir() {
+			continue
+		}
+
+		if err := hashFile(hash, file); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Entries returns a checksum of every file in the teleporter archive, keyed by file name.
+func Entries(payload []byte) (map[string]string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(payload), int64(len(payload)))
+	if err != nil {
+		return nil, fmt.Errorf("read teleporter archive: %w", err)
+	}
+
+	entries := make(map[string]string, len(reader.File))
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		hash := sha256.New()
+		if err := hashFile(hash, file); err != nil {
+			return nil, err
+		}
+		entries[file.Name] = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	return entries, nil
+}
+
+// Of returns a checksum of the JSON representation of value.
+func Of(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("marshal value: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func hashFile(hash io.Writer, file *zip.File) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("open %s: %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	if _, err := io.WriteString(hash, file.Name); err != nil {
+		return err
+	}
+
+	//nolint:gosec // archive is produced by the primary Pi-hole
+	if _, err := io.Copy(hash, reader); err != nil {
+		return fmt.Errorf("read %s: %w", file.Name, err)
+	}
+
+	return nil
+}
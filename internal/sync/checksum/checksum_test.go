@@ -0,0 +1,123 @@
+package checksum
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeleporter_ignoresModified(t *testing.T) {
+	first := archive(t, time.Now(), map[string]string{"etc/pihole/dhcp.leases": "lease"})
+	second := archive(t, time.Now().Add(time.Hour), map[string]string{"etc/pihole/dhcp.leases": "lease"})
+
+	firstSum, err := Teleporter(first, "", nil)
+	require.NoError(t, err)
+	secondSum, err := Teleporter(second, "", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, firstSum, secondSum)
+}
+
+func TestTeleporter_ignoresVolatileEntries(t *testing.T) {
+	first := archive(t, time.Now(), map[string]string{"etc/pihole/dhcp.leases": "lease", "etc/pihole/pihole.toml": "a"})
+	second := archive(t, time.Now(), map[string]string{"etc/pihole/dhcp.leases": "lease", "etc/pihole/pihole.toml": "b"})
+
+	firstSum, err := Teleporter(first, "", nil)
+	require.NoError(t, err)
+	secondSum, err := Teleporter(second, "", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, firstSum, secondSum)
+}
+
+func TestTeleporter_contentChanged(t *testing.T) {
+	first := archive(t, time.Now(), map[string]string{"etc/pihole/dhcp.leases": "lease"})
+	second := archive(t, time.Now(), map[string]string{"etc/pihole/dhcp.leases": "changed"})
+
+	firstSum, err := Teleporter(first, "", nil)
+	require.NoError(t, err)
+	secondSum, err := Teleporter(second, "", nil)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, firstSum, secondSum)
+}
+
+func TestTeleporter_requestChanged(t *testing.T) {
+	payload := archive(t, time.Now(), map[string]string{"etc/pihole/dhcp.leases": "lease"})
+
+	firstSum, err := Teleporter(payload, "", map[string]bool{"adlist": true})
+	require.NoError(t, err)
+	secondSum, err := Teleporter(payload, "", map[string]bool{"adlist": false})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, firstSum, secondSum)
+}
+
+func TestTeleporter_gravity(t *testing.T) {
+	first := archive(t, time.Now(), map[string]string{"etc/pihole/gravity.db": "a", "etc/pihole/dhcp.leases": "lease"})
+	second := archive(t, time.Now(), map[string]string{"etc/pihole/gravity.db": "b", "etc/pihole/dhcp.leases": "lease"})
+
+	firstSum, err := Teleporter(first, "gravity", nil)
+	require.NoError(t, err)
+	secondSum, err := Teleporter(second, "gravity", nil)
+	require.NoError(t, err)
+	changedSum, err := Teleporter(first, "changed", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, firstSum, secondSum)
+	assert.NotEqual(t, firstSum, changedSum)
+}
+
+func TestTeleporter_notArchive(t *testing.T) {
+	sum, err := Teleporter([]byte("not a zip"), "", nil)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, sum)
+}
+
+func TestEntries(t *testing.T) {
+	first := archive(t, time.Now(), map[string]string{"etc/pihole/gravity.db/adlist": "a", "etc/pihole/gravity.db/group": "g"})
+	second := archive(t, time.Now().Add(time.Hour), map[string]string{"etc/pihole/gravity.db/adlist": "b", "etc/pihole/gravity.db/group": "g"})
+
+	firstEntries, err := Entries(first)
+	require.NoError(t, err)
+	secondEntries, err := Entries(second)
+	require.NoError(t, err)
+
+	assert.Len(t, firstEntries, 2)
+	assert.NotEqual(t, firstEntries["etc/pihole/gravity.db/adlist"], secondEntries["etc/pihole/gravity.db/adlist"])
+	assert.Equal(t, firstEntries["etc/pihole/gravity.db/group"], secondEntries["etc/pihole/gravity.db/group"])
+
+	_, err = Entries([]byte("not a zip"))
+	assert.Error(t, err)
+}
+
+func TestOf(t *testing.T) {
+	first, err := Of(map[string]any{"dns": map[string]any{"upstreams": []string{"1.1.1.1"}}})
+	require.NoError(t, err)
+	second, err := Of(map[string]any{"dns": map[string]any{"upstreams": []string{"8.8.8.8"}}})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+}
+
+func archive(t *testing.T, modified time.Time, files map[string]string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+
+	for name, content := range files {
+		fileWriter, err := writer.CreateHeader(&zip.FileHeader{Name: name, Modified: modified, Method: zip.Deflate})
+		require.NoError(t, err)
+		_, err = fileWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
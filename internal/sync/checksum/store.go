@@ -0,0 +1,109 @@
+package checksum
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	KindTeleporter = "teleporter"
+	KindConfig     = "config"
+	// KindGravity is the checksum of the adlists gravity last ran with
+	KindGravity = "gravity"
+)
+
+// Store keeps the last applied checksums per replica, optionally persisted to a file.
+type Store struct {
+	state *state
+	// prefix scopes the replicas of a sync group sharing the state with other groups
+	prefix string
+}
+
+// state holds the checksums of every store sharing a state file.
+type state struct {
+	mu        sync.Mutex
+	path      string
+	checksums map[string]map[string]string
+}
+
+func NewStore(path string) (*Store, error) {
+	store := &Store{state: &state{
+		path:      path,
+		checksums: make(map[string]map[string]string),
+	}}
+
+	if path == "" {
+		return store, nil
+	}
+
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(bytes, &store.state.checksums); err != nil {
+		return nil, fmt.Errorf("parse state file: %w", err)
+	}
+
+	return store, nil
+}
+
+// Group returns a store for the sync group called name that shares the state of s. The checksums of every group are
+// kept apart, so groups using the same state file do not overwrite each other.
+func (s *Store) Group(name string) *Store {
+	return &Store{state: s.state, prefix: s.prefix + name + "/"}
+}
+
+func (s *Store) Matches(replica, kind, checksum string) bool {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	stored, exists := s.state.checksums[s.prefix+replica][kind]
+	return exists && stored == checksum
+}
+
+func (s *Store) Set(replica, kind, checksum string) error {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	key := s.prefix + replica
+	if s.state.checksums[key] == nil {
+		s.state.checksums[key] = make(map[string]string)
+	}
+	s.state.checksums[key][kind] = checksum
+
+	return s.state.save()
+}
+
+func (s *state) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	bytes, err := json.Marshal(s.checksums)
+	if err != nil {
+		return fmt.Errorf("marshal state: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, bytes, 0o600); err != nil {
+		return fmt.Errorf("write state file: %w", err)
+	}
+
+	return nil
+}
+
+// Forget removes all checksums of replica so the next run syncs it again.
+func (s *Store) Forget(replica string) error {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	delete(s.state.checksums, s.prefix+replica)
+
+	return s.state.save()
+}
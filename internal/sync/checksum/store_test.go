@@ -0,0 +1,65 @@
+package checksum
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Matches(t *testing.T) {
+	store, err := NewStore("")
+	require.NoError(t, err)
+
+	assert.False(t, store.Matches("http://replica", KindConfig, ""))
+
+	require.NoError(t, store.Set("http://replica", KindConfig, "abc"))
+
+	assert.True(t, store.Matches("http://replica", KindConfig, "abc"))
+	assert.False(t, store.Matches("http://replica", KindConfig, "def"))
+	assert.False(t, store.Matches("http://replica", KindTeleporter, "abc"))
+	assert.False(t, store.Matches("http://other", KindConfig, "abc"))
+}
+
+func TestStore_persisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	store, err := NewStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Set("http://replica", KindTeleporter, "abc"))
+
+	reloaded, err := NewStore(path)
+	require.NoError(t, err)
+
+	assert.True(t, reloaded.Matches("http://replica", KindTeleporter, "abc"))
+}
+
+func TestStore_Forget(t *testing.T) {
+	store, err := NewStore("")
+	require.NoError(t, err)
+	require.NoError(t, store.Set("http://replica", KindTeleporter, "abc"))
+	require.NoError(t, store.Set("http://replica", KindConfig, "def"))
+
+	require.NoError(t, store.Forget("http://replica"))
+
+	assert.False(t, store.Matches("http://replica", KindTeleporter, "abc"))
+	assert.False(t, store.Matches("http://replica", KindConfig, "def"))
+}
+
+func TestStore_Group(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	store, err := NewStore(path)
+	require.NoError(t, err)
+	home, office := store.Group("home"), store.Group("office")
+	require.NoError(t, home.Set("http://replica", KindConfig, "abc"))
+	require.NoError(t, office.Set("http://replica", KindConfig, "def"))
+
+	reloaded, err := NewStore(path)
+	require.NoError(t, err)
+
+	assert.True(t, reloaded.Group("home").Matches("http://replica", KindConfig, "abc"))
+	assert.True(t, reloaded.Group("office").Matches("http://replica", KindConfig, "def"))
+	assert.False(t, reloaded.Matches("http://replica", KindConfig, "abc"))
+}
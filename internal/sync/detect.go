@@ -0,0 +1,228 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	gosync "sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/sync/checksum"
+	"github.com/lovelaze/nebula-sync/internal/sync/diff"
+	"github.com/lovelaze/nebula-sync/internal/sync/retry"
+)
+
+// Drift is the difference between the replicas and the primary found by a detect run.
+type Drift struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Replicas  []ReplicaDrift `json:"replicas"`
+}
+
+type ReplicaDrift struct {
+	Replica string        `json:"replica"`
+	Config  []diff.Change `json:"config,omitempty"`
+	// Gravity lists the gravity tables, e.g. adlist or group, whose entries differ, and dhcp.leases if the DHCP leases
+	// differ
+	Gravity []string `json:"gravity,omitempty"`
+}
+
+// Detected reports whether at least one replica drifted from the primary.
+func (drift *Drift) Detected() bool {
+	return slices.ContainsFunc(drift.Replicas, func(replica ReplicaDrift) bool {
+		return replica.Drifted()
+	})
+}
+
+func (replica *ReplicaDrift) Drifted() bool {
+	return len(replica.Config) > 0 || len(replica.Gravity) > 0
+}
+
+// DriftCallback is notified of the drift found by every detect run.
+type DriftCallback interface {
+	OnDrift(drift *Drift)
+}
+
+// leasesEntry is the teleporter entry, by file name, holding the DHCP leases. Unlike the other gravity contents they
+// cannot be read through the API.
+const leasesEntry = "dhcp.leases"
+
+// Detect compares every replica to the primary without changing either of them.
+func (target *target) Detect(ctx context.Context, conf *config.Sync) (*Drift, error) {
+	var drift *Drift
+
+	err := target.sync(ctx, conf, func() error {
+		var err error
+		drift, err = target.detect(ctx, conf)
+		return err
+	}, "detect")
+
+	return drift, err
+}
+
+func (target *target) detect(ctx context.Context, conf *config.Sync) (*Drift, error) {
+	gravitySettings, configSettings := conf.GravitySettings, conf.ConfigSettings
+	if conf.FullSync {
+		gravitySettings, configSettings = newFullSyncGravitySettings(), newFullSyncConfigSettings()
+	}
+
+	log.Info().Msg("Detecting drift...")
+	configResponse, err := target.Primary.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gravity, err := target.gravityDetector(ctx, gravitySettings)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := &Drift{Timestamp: time.Now()}
+	var mu gosync.Mutex
+
+	err = target.forEachReplica(func(replica pihole.Client) error {
+		replicaDrift, err := target.detectReplica(ctx, replica, configSettings, configResponse, gravity)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		drift.Replicas = append(drift.Replicas, *replicaDrift)
+		return nil
+	})
+
+	sortByReplica(target.Replicas, drift.Replicas, func(replica ReplicaDrift) string {
+		return replica.Replica
+	})
+
+	return drift, err
+}
+
+// gravityDetector returns the gravity drift of a replica.
+type gravityDetector func(replica pihole.Client) ([]string, error)
+
+// gravityDetector reads the gravity of the primary and returns a detector comparing the gravity entries of replicas
+// to it. DHCP leases are compared by teleporter entry if they are imported.
+func (target *target) gravityDetector(ctx context.Context, gravitySettings *config.GravitySettings) (gravityDetector, error) {
+	primary, err := getGravityRecords(ctx, target.Primary, newFullSyncGravitySettings())
+	if err != nil {
+		return nil, err
+	}
+
+	var primaryEntries map[string]string
+	if target.run == nil || !target.run.records {
+		archive, err := target.Primary.GetTeleporter(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if primaryEntries, err = checksum.Entries(archive); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(replica pihole.Client) ([]string, error) {
+		diff, err := target.gravityDiff(ctx, replica, gravitySettings, primary)
+		if err != nil {
+			return nil, err
+		}
+		drifted := diff.tables()
+
+		settings := target.override(replica).ApplyGravity(gravitySettings)
+		if primaryEntries == nil || (settings != nil && !settings.DHCPLeases) {
+			return drifted, nil
+		}
+
+		var archive []byte
+		if err := retry.Fixed(ctx, func() error {
+			var err error
+			archive, err = replica.GetTeleporter(ctx)
+			return err
+		}, retry.AttemptsGetTeleporter); err != nil {
+			return nil, fmt.Errorf("get replica teleporter: %w", err)
+		}
+
+		replicaEntries, err := checksum.Entries(archive)
+		if err != nil {
+			return nil, err
+		}
+
+		if leasesDrifted(primaryEntries, replicaEntries) {
+			drifted = append(drifted, leasesEntry)
+		}
+		return drifted, nil
+	}, nil
+}
+
+func (target *target) detectReplica(
+	ctx context.Context,
+	replica pihole.Client,
+	configSettings *config.ConfigSettings,
+	configResponse *model.ConfigResponse,
+	gravity gravityDetector,
+) (*ReplicaDrift, error) {
+	override := target.override(replica)
+
+	desired, err := target.transform(replica, createPatchConfigRequest(override.ApplyConfig(configSettings), configResponse).Config.Map())
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := target.configChanges(ctx, replica, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	gravityDrift, err := gravity(replica)
+	if err != nil {
+		return nil, err
+	}
+
+	replicaDrift := &ReplicaDrift{
+		Replica: replica.String(),
+		Config:  changes,
+		Gravity: gravityDrift,
+	}
+	replicaDrift.log()
+
+	return replicaDrift, nil
+}
+
+// leasesDrifted reports whether the DHCP leases differ between the teleporter entries of primary and replica.
+func leasesDrifted(primary, replica map[string]string) bool {
+	leases := func(entries map[string]string) string {
+		for name, sum := range entries {
+			if path.Base(name) == leasesEntry {
+				return sum
+			}
+		}
+		return ""
+	}
+
+	return leases(primary) != leases(replica)
+}
+
+func (replica *ReplicaDrift) log() {
+	logger := log.With().Str("replica", replica.Replica).Logger()
+
+	if !replica.Drifted() {
+		logger.Info().Msg("No drift detected")
+		return
+	}
+
+	for _, change := range replica.Config {
+		logger.Warn().
+			Str("key", change.Key).
+			Any("primary", change.To).
+			Any("replica", change.From).
+			Msg("Config drifted")
+	}
+	if len(replica.Gravity) > 0 {
+		logger.Warn().Strs("entries", replica.Gravity).Msg("Gravity drifted")
+	}
+}
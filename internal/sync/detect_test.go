@@ -0,0 +1,94 @@
+package sync
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/sync/diff"
+)
+
+func TestTarget_Detect(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	target := NewTarget(primary, []pihole.Client{replica}, 1, nil)
+
+	primaryConfig := emptyConfigResponse()
+	primaryConfig.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}}
+	replicaConfig := emptyConfigResponse()
+	replicaConfig.Config["dns"] = map[string]any{"upstreams": []any{"8.8.8.8"}}
+
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	replica.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(primaryConfig, nil)
+	expectGravityRecords(primary, primaryRecords())
+	primary.EXPECT().GetTeleporter(mock.Anything).Once().Return(teleporterArchive(t, map[string]string{
+		"etc/pihole/pihole.toml": "primary",
+		"etc/pihole/gravity.db":  "primary gravity",
+		"etc/pihole/dhcp.leases": "primary leases",
+	}), nil)
+
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(replicaConfig, nil)
+	expectGravityRecords(replica, replicaRecords())
+	replica.EXPECT().GetTeleporter(mock.Anything).Once().Return(teleporterArchive(t, map[string]string{
+		"etc/pihole/pihole.toml": "replica",
+		"etc/pihole/gravity.db":  "replica gravity",
+		"etc/pihole/dhcp.leases": "replica leases",
+	}), nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	primary.EXPECT().HasSession().Return(true)
+
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	replica.EXPECT().HasSession().Return(true)
+	replica.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+
+	drift, err := target.Detect(context.Background(), &config.Sync{FullSync: true})
+	require.NoError(t, err)
+
+	assert.True(t, drift.Detected())
+	require.Len(t, drift.Replicas, 1)
+	assert.Equal(t, "http://replica", drift.Replicas[0].Replica)
+	assert.Equal(t, []diff.Change{
+		{Key: "dns.upstreams", Path: []string{"dns", "upstreams"}, From: []any{"8.8.8.8"}, To: []any{"1.1.1.1"}},
+	}, drift.Replicas[0].Config)
+	assert.Equal(t, []string{tableAdlist, tableDomainlist, tableGroup, leasesEntry}, drift.Replicas[0].Gravity)
+}
+
+func Test_leasesDrifted(t *testing.T) {
+	primary := map[string]string{"etc/pihole/dhcp.leases": "a", "etc/pihole/gravity.db": "g"}
+
+	assert.False(t, leasesDrifted(primary, map[string]string{"etc/pihole/dhcp.leases": "a", "etc/pihole/gravity.db": "h"}))
+	assert.True(t, leasesDrifted(primary, map[string]string{"etc/pihole/dhcp.leases": "b"}))
+	assert.True(t, leasesDrifted(primary, map[string]string{}))
+}
+
+func teleporterArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+
+	for name, content := range files {
+		fileWriter, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = fileWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
@@ -0,0 +1,119 @@
+package diff
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type Change struct {
+	// Key is the dotted path of the change for display, dots that are part of a key are escaped with a backslash
+	Key string `json:"key"`
+	// Path holds the keys leading to the change
+	Path []string `json:"-"`
+	From any      `json:"from"`
+	To   any      `json:"to"`
+}
+
+// Changes returns every leaf of desired that is missing or different in current, sorted by key.
+func Changes(current, desired map[string]any) []Change {
+	changes := []Change{}
+	collect(&changes, nil, current, desired)
+
+	slices.SortFunc(changes, func(a, b Change) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return changes
+}
+
+func collect(changes *[]Change, path []string, current, desired map[string]any) {
+	for key, value := range desired {
+		keyPath := append(slices.Clone(path), key)
+		currentValue, exists := current[key]
+
+		if nested, ok := value.(map[string]any); ok {
+			currentNested, _ := currentValue.(map[string]any)
+			collect(changes, keyPath, currentNested, nested)
+			continue
+		}
+
+		if !exists || !reflect.DeepEqual(currentValue, value) {
+			*changes = append(*changes, Change{
+				Key:  joinKey(keyPath),
+				Path: keyPath,
+				From: currentValue,
+				To:   value,
+			})
+		}
+	}
+}
+
+// Patch builds a nested object containing only the desired values of changes.
+func Patch(changes []Change) map[string]any {
+	patch := make(map[string]any)
+
+	for _, change := range changes {
+		keys := change.Path
+		current := patch
+
+		for _, key := range keys[:len(keys)-1] {
+			next, ok := current[key].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				current[key] = next
+			}
+			current = next
+		}
+
+		current[keys[len(keys)-1]] = change.To
+	}
+
+	return patch
+}
+
+// joinKey joins keys with dots, escaping the dots that are part of a key like config filters do.
+func joinKey(keys []string) string {
+	escaped := make([]string, 0, len(keys))
+	for _, key := range keys {
+		escaped = append(escaped, strings.ReplaceAll(key, ".", `\.`))
+	}
+	return strings.Join(escaped, ".")
+}
+
+func Keys(changes []Change) []string {
+	keys := make([]string, 0, len(changes))
+	for _, change := range changes {
+		keys = append(keys, change.Key)
+	}
+	return keys
+}
+
+// Existing splits changes into those of keys that exist in current and the keys of those that do not.
+func Existing(current map[string]any, changes []Change) ([]Change, []string) {
+	var existing []Change
+	var missing []string
+
+	for _, change := range changes {
+		if exists(current, change.Path) {
+			existing = append(existing, change)
+		} else {
+			missing = append(missing, change.Key)
+		}
+	}
+
+	return existing, missing
+}
+
+func exists(current map[string]any, keys []string) bool {
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			return false
+		}
+		current = next
+	}
+
+	_, ok := current[keys[len(keys)-1]]
+	return ok
+}
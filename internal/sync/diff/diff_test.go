@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChanges(t *testing.T) {
+	current := map[string]any{
+		"upstreams": []any{"8.8.8.8"},
+		"cache":     map[string]any{"size": 10000.0, "optimizer": 3600.0},
+		"interface": "eth0",
+	}
+	desired := map[string]any{
+		"upstreams": []any{"1.1.1.1"},
+		"cache":     map[string]any{"size": 10000.0, "optimizer": 600.0},
+		"interface": "eth0",
+		"domain":    map[string]any{"name": "lan"},
+	}
+
+	changes := Changes(current, desired)
+
+	assert.Equal(t, []Change{
+		{Key: "cache.optimizer", Path: []string{"cache", "optimizer"}, From: 3600.0, To: 600.0},
+		{Key: "domain.name", Path: []string{"domain", "name"}, From: nil, To: "lan"},
+		{Key: "upstreams", Path: []string{"upstreams"}, From: []any{"8.8.8.8"}, To: []any{"1.1.1.1"}},
+	}, changes)
+}
+
+func TestChanges_none(t *testing.T) {
+	data := map[string]any{
+		"cache": map[string]any{"size": 10000.0},
+	}
+
+	assert.Empty(t, Changes(data, data))
+}
+
+func TestChanges_nilCurrent(t *testing.T) {
+	changes := Changes(nil, map[string]any{"active": true})
+
+	assert.Equal(t, []Change{{Key: "active", Path: []string{"active"}, From: nil, To: true}}, changes)
+}
+
+func TestChanges_dottedKey(t *testing.T) {
+	changes := Changes(nil, map[string]any{"dns": map[string]any{"hosts.local": "lan"}})
+
+	assert.Equal(t, []Change{{Key: `dns.hosts\.local`, Path: []string{"dns", "hosts.local"}, To: "lan"}}, changes)
+	assert.Equal(t, map[string]any{"dns": map[string]any{"hosts.local": "lan"}}, Patch(changes))
+}
+
+func TestPatch(t *testing.T) {
+	patch := Patch([]Change{
+		{Key: "dns.cache.optimizer", Path: []string{"dns", "cache", "optimizer"}, To: 600.0},
+		{Key: "dns.upstreams", Path: []string{"dns", "upstreams"}, To: []any{"1.1.1.1"}},
+		{Key: "dhcp.active", Path: []string{"dhcp", "active"}, To: false},
+	})
+
+	assert.Equal(t, map[string]any{
+		"dns": map[string]any{
+			"cache":     map[string]any{"optimizer": 600.0},
+			"upstreams": []any{"1.1.1.1"},
+		},
+		"dhcp": map[string]any{"active": false},
+	}, patch)
+}
+
+func TestKeys(t *testing.T) {
+	keys := Keys([]Change{{Key: "upstreams"}, {Key: "cache.size"}})
+
+	assert.Equal(t, []string{"upstreams", "cache.size"}, keys)
+}
+
+func TestExisting(t *testing.T) {
+	current := map[string]any{
+		"dns": map[string]any{"upstreams": []any{}, "cache": map[string]any{"size": 10000.0}},
+	}
+
+	existing, missing := Existing(current, []Change{
+		{Key: "dns.upstreams", Path: []string{"dns", "upstreams"}, To: []any{"1.1.1.1"}},
+		{Key: "dns.cache.size", Path: []string{"dns", "cache", "size"}, To: 5000.0},
+		{Key: "dns.cache.optimizer", Path: []string{"dns", "cache", "optimizer"}, To: 600.0},
+		{Key: "ntp.ipv4.active", Path: []string{"ntp", "ipv4", "active"}, To: true},
+	})
+
+	assert.Equal(t, []Change{
+		{Key: "dns.upstreams", Path: []string{"dns", "upstreams"}, To: []any{"1.1.1.1"}},
+		{Key: "dns.cache.size", Path: []string{"dns", "cache", "size"}, To: 5000.0},
+	}, existing)
+	assert.Equal(t, []string{"dns.cache.optimizer", "ntp.ipv4.active"}, missing)
+}
@@ -2,6 +2,8 @@ package filter
 
 import (
 	"fmt"
+	"path"
+	"regexp"
 	"strings"
 
 	"github.com/rs/zerolog/log"
@@ -14,6 +16,10 @@ const (
 	Exclude
 )
 
+// regexPrefix marks a key as a regular expression matched against the full dotted path of every config key, section
+// included.
+const regexPrefix = "re:"
+
 func (ft Type) String() string {
 	var s string
 	switch ft {
@@ -25,25 +31,56 @@ func (ft Type) String() string {
 	return s
 }
 
-func ByType(filter Type, keys []string, json map[string]any) (map[string]any, error) {
+// ByType includes or excludes keys of json, the config of section. A key is a dotted path relative to section where
+// each segment may be a glob pattern (`reply.*.IPv4`), or a regular expression prefixed with `re:` that is matched
+// against the full path, section included (`re:^dhcp\.(start|end)$`). Dots that are part of a key are escaped with a
+// backslash.
+func ByType(filter Type, section string, keys []string, json map[string]any) (map[string]any, error) {
 	switch filter {
 	case Include:
-		return includeKeys(json, keys), nil
+		return includeKeys(json, section, keys), nil
 	case Exclude:
-		return excludeKeys(json, keys), nil
+		return excludeKeys(json, section, keys), nil
 	default:
 		return nil, fmt.Errorf("unknown filter type: %v", filter)
 	}
 }
 
-func includeKeys(jsonData map[string]any, keys []string) map[string]any {
+// Remove is like ByType with Exclude, but keys missing in json are silently ignored.
+func Remove(section string, keys []string, json map[string]any) map[string]any {
+	return exclude(json, section, keys, false)
+}
+
+// Validate returns an error for the first key that is not a valid selector.
+func Validate(keys []string) error {
+	for _, key := range keys {
+		if _, err := parseSelector(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func includeKeys(jsonData map[string]any, section string, keys []string) map[string]any {
 	result := make(map[string]any)
 
 	for _, key := range keys {
-		value := getNestedValue(jsonData, key)
-		if value != nil {
-			setNestedValue(result, key, value)
-		} else {
+		selector, err := parseSelector(key)
+		if err != nil {
+			log.Warn().Err(err).Msg("Ignoring invalid config filter")
+			continue
+		}
+
+		found := false
+		for _, match := range selector.find(section, jsonData) {
+			if match.value == nil {
+				continue
+			}
+			found = true
+			setNestedValue(result, match.path, copyValue(match.value))
+		}
+
+		if !found && selector.exact() {
 			log.Warn().Str("key", key).Msg("Attempted to include missing config")
 		}
 	}
@@ -51,36 +88,146 @@ func includeKeys(jsonData map[string]any, keys []string) map[string]any {
 	return result
 }
 
-func excludeKeys(jsonData map[string]any, keys []string) map[string]any {
+func excludeKeys(jsonData map[string]any, section string, keys []string) map[string]any {
+	return exclude(jsonData, section, keys, true)
+}
+
+func exclude(jsonData map[string]any, section string, keys []string, warn bool) map[string]any {
 	result := deepCopy(jsonData)
 
 	for _, key := range keys {
-		removeNestedKey(result, strings.Split(key, "."))
+		selector, err := parseSelector(key)
+		if err != nil {
+			log.Warn().Err(err).Msg("Ignoring invalid config filter")
+			continue
+		}
+
+		matches := selector.find(section, result)
+		if len(matches) == 0 && warn && selector.exact() {
+			log.Warn().Str("key", key).Msg("Attempted to exclude missing config")
+		}
+
+		for _, match := range matches {
+			removeNestedKey(result, match.path)
+		}
 	}
 
 	return result
 }
 
-func getNestedValue(data map[string]any, key string) any {
-	keys := strings.Split(key, ".")
-	current := data
-	for i, k := range keys {
-		if next, ok := current[k].(map[string]any); ok {
-			current = next
-			if i == len(keys)-1 {
-				return next
+// selector matches config keys by their path.
+type selector struct {
+	key      string
+	segments []string
+	regex    *regexp.Regexp
+}
+
+type match struct {
+	path  []string
+	value any
+}
+
+func parseSelector(key string) (*selector, error) {
+	if pattern, ok := strings.CutPrefix(key, regexPrefix); ok {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config filter %q: %w", key, err)
+		}
+		return &selector{key: key, regex: regex}, nil
+	}
+
+	segments := SplitKey(key)
+	for _, segment := range segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, fmt.Errorf("invalid config filter %q: %w", key, err)
+		}
+	}
+
+	return &selector{key: key, segments: segments}, nil
+}
+
+// SplitKey splits key on every dot that is not escaped with a backslash.
+func SplitKey(key string) []string {
+	var segments []string
+	var segment strings.Builder
+
+	for i := 0; i < len(key); i++ {
+		switch {
+		case key[i] == '\\' && i+1 < len(key) && key[i+1] == '.':
+			segment.WriteByte('.')
+			i++
+		case key[i] == '.':
+			segments = append(segments, segment.String())
+			segment.Reset()
+		default:
+			segment.WriteByte(key[i])
+		}
+	}
+
+	return append(segments, segment.String())
+}
+
+// joinKey joins keys with dots, escaping the dots that are part of a key.
+func joinKey(keys []string) string {
+	escaped := make([]string, 0, len(keys))
+	for _, key := range keys {
+		escaped = append(escaped, strings.ReplaceAll(key, ".", `\.`))
+	}
+	return strings.Join(escaped, ".")
+}
+
+// exact reports whether the selector names a single key rather than a pattern.
+func (s *selector) exact() bool {
+	if s.regex != nil {
+		return false
+	}
+	for _, segment := range s.segments {
+		if strings.ContainsAny(segment, `*?[\`) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *selector) matches(section string, keyPath []string) bool {
+	if s.regex != nil {
+		return s.regex.MatchString(joinKey(append([]string{section}, keyPath...)))
+	}
+
+	if len(keyPath) != len(s.segments) {
+		return false
+	}
+	for i, segment := range s.segments {
+		if matched, _ := path.Match(segment, keyPath[i]); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// find returns every key of data, the config of section, the selector matches. Keys below a match are not visited.
+func (s *selector) find(section string, data map[string]any) []match {
+	var matches []match
+
+	var visit func(current map[string]any, parent []string)
+	visit = func(current map[string]any, parent []string) {
+		for key, value := range current {
+			keyPath := append(parent[:len(parent):len(parent)], key)
+			if s.matches(section, keyPath) {
+				matches = append(matches, match{path: keyPath, value: value})
+				continue
+			}
+			if nested, ok := value.(map[string]any); ok {
+				visit(nested, keyPath)
 			}
-		} else if value, ok := current[k]; ok {
-			return value
-		} else {
-			return nil
 		}
 	}
-	return current
+	visit(data, nil)
+
+	return matches
 }
 
-func setNestedValue(target map[string]any, key string, value any) {
-	keys := strings.Split(key, ".")
+func setNestedValue(target map[string]any, keys []string, value any) {
 	current := target
 
 	for _, k := range keys[:len(keys)-1] {
@@ -104,9 +251,7 @@ func removeNestedKey(target map[string]any, keys []string) {
 	currentKey := keys[0]
 	remainingKeys := keys[1:]
 
-	_, exists := target[currentKey]
-	if !exists {
-		log.Warn().Str("key", strings.Join(keys, ".")).Msg("Attempted to exclude missing config")
+	if _, exists := target[currentKey]; !exists {
 		return
 	}
 
@@ -123,6 +268,13 @@ func removeNestedKey(target map[string]any, keys []string) {
 	}
 }
 
+func copyValue(value any) any {
+	if nested, ok := value.(map[string]any); ok {
+		return deepCopy(nested)
+	}
+	return value
+}
+
 func deepCopy(original map[string]any) map[string]any {
 	copied := make(map[string]any)
 	for key, value := range original {
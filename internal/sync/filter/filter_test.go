@@ -14,7 +14,7 @@ import (
 func TestFilter_ByType_Include(t *testing.T) {
 	filterKeys := []string{"cache", "upstreams", "interface"}
 	data := loadDNSData()
-	result, err := ByType(Include, filterKeys, data)
+	result, err := ByType(Include, "dns", filterKeys, data)
 	require.NoError(t, err)
 	assert.Len(t, filterKeys, 3)
 	assert.Len(t, result, 3)
@@ -32,7 +32,7 @@ func TestFilter_ByType_Include(t *testing.T) {
 func TestFilter_ByType_Exclude(t *testing.T) {
 	filterKeys := []string{"cache", "upstreams", "interface"}
 	data := loadDNSData()
-	result, err := ByType(Exclude, filterKeys, data)
+	result, err := ByType(Exclude, "dns", filterKeys, data)
 	require.NoError(t, err)
 	assert.Equal(t, len(result), len(data)-len(filterKeys))
 
@@ -49,7 +49,7 @@ func TestFilter_ByType_Exclude(t *testing.T) {
 func TestFilter_ByType_MultipleNested(t *testing.T) {
 	filterKeys := []string{"reply.host.force4", "reply.host.IPv4", "reply.blocking.force4"}
 	data := loadDNSData()
-	result, err := ByType(Include, filterKeys, data)
+	result, err := ByType(Include, "dns", filterKeys, data)
 	require.NoError(t, err)
 	assert.Len(t, result, 1)
 
@@ -88,7 +88,7 @@ func TestFilter_IncludeKeys(t *testing.T) {
 	}
 
 	keys := []string{"a", "b.c", "e"}
-	result := includeKeys(data, keys)
+	result := includeKeys(data, "dns", keys)
 
 	assert.Equal(t, 1, result["a"])
 	assert.Equal(t, 2, result["b"].(map[string]any)["c"])
@@ -100,7 +100,7 @@ func TestFilter_IncludeKeys(t *testing.T) {
 func TestFilter_IncludeKeys_MissingKey(t *testing.T) {
 	data := map[string]any{"a": 1}
 	keys := []string{"b"}
-	result := includeKeys(data, keys)
+	result := includeKeys(data, "dns", keys)
 
 	assert.Empty(t, result)
 }
@@ -113,7 +113,7 @@ func TestFilter_ExcludeKeys(t *testing.T) {
 	}
 
 	keys := []string{"a", "b.c"}
-	result := excludeKeys(data, keys)
+	result := excludeKeys(data, "dns", keys)
 
 	assert.NotContains(t, result, "a")
 	assert.NotContains(t, result["b"].(map[string]any), "c")
@@ -124,7 +124,95 @@ func TestFilter_ExcludeKeys(t *testing.T) {
 func TestFilter_ExcludeKeys_NonExistentKey(t *testing.T) {
 	data := map[string]any{"a": 1}
 	keys := []string{"b"}
-	result := excludeKeys(data, keys)
+	result := excludeKeys(data, "dns", keys)
 
 	assert.Equal(t, data, result)
 }
+
+func TestFilter_IncludeKeys_Glob(t *testing.T) {
+	data := map[string]any{
+		"reply": map[string]any{
+			"host":     map[string]any{"IPv4": "1.1.1.1", "IPv6": "::1"},
+			"blocking": map[string]any{"IPv4": "2.2.2.2", "IPv6": "::2"},
+		},
+		"dnsmasq_lines": []any{"a"},
+		"dnsmasq_other": true,
+		"nice":          -10,
+	}
+
+	result := includeKeys(data, "dns", []string{"reply.*.IPv4", "dnsmasq_*"})
+
+	assert.Equal(t, map[string]any{
+		"reply": map[string]any{
+			"host":     map[string]any{"IPv4": "1.1.1.1"},
+			"blocking": map[string]any{"IPv4": "2.2.2.2"},
+		},
+		"dnsmasq_lines": []any{"a"},
+		"dnsmasq_other": true,
+	}, result)
+}
+
+func TestFilter_IncludeKeys_Regex(t *testing.T) {
+	data := map[string]any{"start": "10.0.0.1", "end": "10.0.0.255", "router": "10.0.0.1", "leaseTime": "24h"}
+
+	result := includeKeys(data, "dhcp", []string{`re:^dhcp\.(start|end)$`})
+
+	assert.Equal(t, map[string]any{"start": "10.0.0.1", "end": "10.0.0.255"}, result)
+}
+
+func TestFilter_ExcludeKeys_Glob(t *testing.T) {
+	data := map[string]any{
+		"reply": map[string]any{
+			"host":     map[string]any{"IPv4": "1.1.1.1"},
+			"blocking": map[string]any{"IPv4": "2.2.2.2", "force4": true},
+		},
+		"nice": -10,
+	}
+
+	result := excludeKeys(data, "dns", []string{"reply.*.IPv4"})
+
+	assert.Equal(t, map[string]any{
+		"reply": map[string]any{"blocking": map[string]any{"force4": true}},
+		"nice":  -10,
+	}, result)
+	assert.Contains(t, data["reply"].(map[string]any), "host")
+}
+
+func TestFilter_ExcludeKeys_Regex(t *testing.T) {
+	data := map[string]any{"api": map[string]any{"pwhash": "x", "app_pwhash": "y", "maxSessions": 16}}
+
+	result := excludeKeys(data, "webserver", []string{`re:^webserver\.api\..*pwhash$`})
+
+	assert.Equal(t, map[string]any{"api": map[string]any{"maxSessions": 16}}, result)
+}
+
+func TestFilter_EscapedDot(t *testing.T) {
+	data := map[string]any{
+		"a.b": 1,
+		"a":   map[string]any{"b": 2},
+	}
+
+	assert.Equal(t, map[string]any{"a.b": 1}, includeKeys(data, "dns", []string{`a\.b`}))
+	assert.Equal(t, map[string]any{"a": map[string]any{"b": 2}}, includeKeys(data, "dns", []string{"a.b"}))
+	assert.Equal(t, map[string]any{"a": map[string]any{"b": 2}}, excludeKeys(data, "dns", []string{`a\.b`}))
+	assert.Equal(t, map[string]any{"a.b": 1}, includeKeys(data, "dns", []string{`re:^dns\.a\\\.b$`}))
+}
+
+func TestFilter_Validate(t *testing.T) {
+	require.NoError(t, Validate([]string{"upstreams", "reply.*.IPv4", `re:^(start|end)$`, `a\.b`}))
+	require.Error(t, Validate([]string{"re:("}))
+	require.Error(t, Validate([]string{"reply.[.IPv4"}))
+}
+
+func TestFilter_splitKey(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, SplitKey("a.b"))
+	assert.Equal(t, []string{"a.b", "c"}, SplitKey(`a\.b.c`))
+	assert.Equal(t, []string{"a"}, SplitKey("a"))
+}
+
+func TestFilter_Remove(t *testing.T) {
+	data := map[string]any{"a": 1, "b": 2}
+
+	assert.Equal(t, map[string]any{"b": 2}, Remove("dns", []string{"a", "missing"}, data))
+	assert.Len(t, data, 2)
+}
@@ -0,0 +1,32 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/sync/retry"
+)
+
+// RunGravity runs gravity on the primary and every replica regardless of whether their adlists changed, so the
+// contents of the lists are refreshed without syncing.
+func (target *target) RunGravity(ctx context.Context, conf *config.Sync) error {
+	return target.sync(ctx, conf, func() error {
+		log.Info().Msg("Refreshing gravity...")
+
+		if err := target.Primary.PostRunGravity(ctx); err != nil {
+			return err
+		}
+
+		return target.forEachReplica(func(replica pihole.Client) error {
+			if err := retry.Fixed(ctx, func() error {
+				return replica.PostRunGravity(ctx)
+			}, retry.AttemptsPostRunGravity); err != nil {
+				return err
+			}
+			return target.awaitReady(ctx, replica)
+		})
+	}, "gravity")
+}
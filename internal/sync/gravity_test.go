@@ -0,0 +1,40 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/sync/checksum"
+)
+
+func TestTarget_RunGravity(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	checksums, err := checksum.NewStore("")
+	require.NoError(t, err)
+	require.NoError(t, checksums.Set("http://replica", checksum.KindGravity, "adlists"))
+
+	target := NewTarget(primary, []pihole.Client{replica}, 1, checksums)
+
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	replica.EXPECT().String().Return("http://replica")
+	replica.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	primary.EXPECT().PostRunGravity(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostRunGravity(mock.Anything).Once().Return(nil)
+	primary.EXPECT().HasSession().Return(true)
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	replica.EXPECT().HasSession().Return(true)
+	replica.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+
+	require.NoError(t, target.RunGravity(context.Background(), &config.Sync{}))
+}
@@ -0,0 +1,248 @@
+package merge
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type Strategy string
+
+const (
+	// Replace overwrites the matching elements of the replica with those of the primary.
+	Replace Strategy = "replace"
+	// Union adds the matching elements of the primary to those of the replica.
+	Union Strategy = "union"
+	// PrimaryWinsByKey replaces replica elements that share a key with a primary element and keeps all others.
+	PrimaryWinsByKey Strategy = "primary-wins-by-key"
+)
+
+// regexPrefix marks a pattern as a regular expression instead of a glob.
+const regexPrefix = "re:"
+
+// elementKeys returns the natural key of an element for every supported array.
+var elementKeys = map[string]func(element string) string{
+	// "192.168.1.10 nas.lan nas" is keyed by its hostnames
+	"dns.hosts": func(element string) string {
+		fields := strings.Fields(element)
+		if len(fields) < 2 {
+			return element
+		}
+		return strings.Join(fields[1:], " ")
+	},
+	// "alias.lan,target.lan[,ttl]" is keyed by its alias
+	"dns.cnameRecords": firstField,
+	"dns.upstreams":    strings.TrimSpace,
+	// "aa:bb:cc:dd:ee:ff,192.168.1.10,nas" is keyed by its MAC address or hostname
+	"dhcp.hosts": firstField,
+}
+
+// Keys returns the config keys of all arrays that support element rules.
+func Keys() []string {
+	keys := make([]string, 0, len(elementKeys))
+	for key := range elementKeys {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Rule controls how the elements of an array config value are synced. Only elements matching Include and not
+// matching Exclude are managed by the primary, all other elements of the replica are kept.
+type Rule struct {
+	Key      string
+	Strategy Strategy
+
+	include []pattern
+	exclude []pattern
+	key     func(element string) string
+}
+
+type pattern struct {
+	glob  string
+	regex *regexp.Regexp
+}
+
+// NewRule validates and compiles a rule for the array at key, e.g. dns.hosts.
+func NewRule(key string, strategy Strategy, include, exclude []string) (*Rule, error) {
+	elementKey, ok := elementKeys[key]
+	if !ok {
+		return nil, fmt.Errorf("%s: element rules are not supported", key)
+	}
+
+	switch strategy {
+	case "":
+		strategy = Replace
+	case Replace, Union, PrimaryWinsByKey:
+	default:
+		return nil, fmt.Errorf("%s: unknown merge strategy %q", key, strategy)
+	}
+
+	rule := &Rule{Key: key, Strategy: strategy, key: elementKey}
+
+	var err error
+	if rule.include, err = compile(include); err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	if rule.exclude, err = compile(exclude); err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	return rule, nil
+}
+
+func compile(patterns []string) ([]pattern, error) {
+	compiled := make([]pattern, 0, len(patterns))
+
+	for _, p := range patterns {
+		if expression, ok := strings.CutPrefix(p, regexPrefix); ok {
+			regex, err := regexp.Compile(expression)
+			if err != nil {
+				return nil, fmt.Errorf("invalid element pattern %q: %w", p, err)
+			}
+			compiled = append(compiled, pattern{regex: regex})
+			continue
+		}
+
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid element pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, pattern{glob: p})
+	}
+
+	return compiled, nil
+}
+
+func (p pattern) matches(element string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(element)
+	}
+	matched, _ := path.Match(p.glob, element)
+	return matched
+}
+
+// managed reports whether element is synced from the primary.
+func (rule *Rule) managed(element string) bool {
+	matches := func(patterns []pattern) bool {
+		return slices.ContainsFunc(patterns, func(p pattern) bool {
+			return p.matches(element)
+		})
+	}
+
+	if len(rule.include) > 0 && !matches(rule.include) {
+		return false
+	}
+	return !matches(rule.exclude)
+}
+
+// Apply returns a copy of desired where every array with a rule is merged with the array in current.
+// Arrays missing in desired are skipped, arrays missing in current are treated as empty.
+func Apply(rules []*Rule, desired, current map[string]any) map[string]any {
+	if len(rules) == 0 {
+		return desired
+	}
+
+	result := deepCopy(desired)
+	for _, rule := range rules {
+		keys := strings.Split(rule.Key, ".")
+
+		parent := lookupParent(result, keys)
+		if parent == nil {
+			continue
+		}
+		last := keys[len(keys)-1]
+		primary, ok := parent[last].([]any)
+		if !ok {
+			continue
+		}
+
+		var replica []any
+		if currentParent := lookupParent(current, keys); currentParent != nil {
+			replica, _ = currentParent[last].([]any)
+		}
+
+		parent[last] = rule.merge(primary, replica)
+	}
+
+	return result
+}
+
+func (rule *Rule) merge(primary, replica []any) []any {
+	managed := make([]any, 0, len(primary))
+	for _, element := range primary {
+		if rule.managed(fmt.Sprint(element)) {
+			managed = append(managed, element)
+		}
+	}
+
+	merged := []any{}
+	switch rule.Strategy {
+	case Union:
+		merged = append(merged, replica...)
+		for _, element := range managed {
+			if !slices.Contains(merged, element) {
+				merged = append(merged, element)
+			}
+		}
+	case PrimaryWinsByKey:
+		byKey := make(map[string][]any, len(managed))
+		for _, element := range managed {
+			key := rule.key(fmt.Sprint(element))
+			byKey[key] = append(byKey[key], element)
+		}
+
+		// keep the order of the replica so an array already in sync is not patched again
+		placed := make(map[string]bool, len(byKey))
+		for _, element := range append(slices.Clone(replica), managed...) {
+			key := rule.key(fmt.Sprint(element))
+			if _, ok := byKey[key]; !ok {
+				merged = append(merged, element)
+				continue
+			}
+			if !placed[key] {
+				merged = append(merged, byKey[key]...)
+				placed[key] = true
+			}
+		}
+	default:
+		for _, element := range replica {
+			if !rule.managed(fmt.Sprint(element)) {
+				merged = append(merged, element)
+			}
+		}
+		merged = append(merged, managed...)
+	}
+
+	return merged
+}
+
+func firstField(element string) string {
+	field, _, _ := strings.Cut(element, ",")
+	return strings.TrimSpace(field)
+}
+
+func lookupParent(config map[string]any, keys []string) map[string]any {
+	current := config
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+func deepCopy(original map[string]any) map[string]any {
+	copied := make(map[string]any, len(original))
+	for key, value := range original {
+		if nested, ok := value.(map[string]any); ok {
+			copied[key] = deepCopy(nested)
+		} else {
+			copied[key] = value
+		}
+	}
+	return copied
+}
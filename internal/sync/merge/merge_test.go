@@ -0,0 +1,105 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hosts(elements ...any) map[string]any {
+	return map[string]any{"dns": map[string]any{"hosts": elements}}
+}
+
+func TestNewRule(t *testing.T) {
+	rule, err := NewRule("dns.hosts", "", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Replace, rule.Strategy)
+
+	_, err = NewRule("dns.domain", Union, nil, nil)
+	require.ErrorContains(t, err, "not supported")
+
+	_, err = NewRule("dns.hosts", "merge", nil, nil)
+	require.ErrorContains(t, err, "unknown merge strategy")
+
+	_, err = NewRule("dns.hosts", Union, []string{"re:("}, nil)
+	require.ErrorContains(t, err, "invalid element pattern")
+}
+
+func TestApply_Replace(t *testing.T) {
+	rule, err := NewRule("dns.hosts", Replace, nil, nil)
+	require.NoError(t, err)
+
+	result := Apply([]*Rule{rule}, hosts("10.0.0.1 a.lan"), hosts("10.0.0.2 b.lan"))
+
+	assert.Equal(t, hosts("10.0.0.1 a.lan"), result)
+}
+
+func TestApply_Replace_Include(t *testing.T) {
+	rule, err := NewRule("dns.hosts", Replace, []string{"*.lan"}, nil)
+	require.NoError(t, err)
+
+	desired := hosts("10.0.0.1 a.lan", "10.0.0.9 primary.home")
+	current := hosts("10.0.0.2 old.lan", "10.0.0.3 local.home")
+
+	result := Apply([]*Rule{rule}, desired, current)
+
+	assert.Equal(t, hosts("10.0.0.3 local.home", "10.0.0.1 a.lan"), result)
+	assert.Equal(t, hosts("10.0.0.1 a.lan", "10.0.0.9 primary.home"), desired)
+}
+
+func TestApply_Union(t *testing.T) {
+	rule, err := NewRule("dns.upstreams", Union, nil, []string{"re:^127\\."})
+	require.NoError(t, err)
+
+	desired := map[string]any{"dns": map[string]any{"upstreams": []any{"1.1.1.1", "8.8.8.8", "127.0.0.1#5335"}}}
+	current := map[string]any{"dns": map[string]any{"upstreams": []any{"9.9.9.9", "1.1.1.1"}}}
+
+	result := Apply([]*Rule{rule}, desired, current)
+
+	assert.Equal(t, []any{"9.9.9.9", "1.1.1.1", "8.8.8.8"}, result["dns"].(map[string]any)["upstreams"])
+}
+
+func TestApply_PrimaryWinsByKey(t *testing.T) {
+	rule, err := NewRule("dns.cnameRecords", PrimaryWinsByKey, nil, nil)
+	require.NoError(t, err)
+
+	desired := map[string]any{"dns": map[string]any{"cnameRecords": []any{"a.lan,primary.lan", "c.lan,primary.lan"}}}
+	current := map[string]any{"dns": map[string]any{"cnameRecords": []any{"b.lan,local.lan", "a.lan,local.lan"}}}
+
+	result := Apply([]*Rule{rule}, desired, current)
+
+	assert.Equal(t,
+		[]any{"b.lan,local.lan", "a.lan,primary.lan", "c.lan,primary.lan"},
+		result["dns"].(map[string]any)["cnameRecords"],
+	)
+}
+
+func TestApply_PrimaryWinsByKey_InSync(t *testing.T) {
+	rule, err := NewRule("dns.hosts", PrimaryWinsByKey, nil, nil)
+	require.NoError(t, err)
+
+	current := hosts("10.0.0.1 a.lan", "10.0.0.3 local.lan")
+
+	result := Apply([]*Rule{rule}, hosts("10.0.0.1 a.lan"), current)
+
+	assert.Equal(t, current, result)
+}
+
+func TestApply_MissingArray(t *testing.T) {
+	rule, err := NewRule("dhcp.hosts", Union, nil, nil)
+	require.NoError(t, err)
+
+	desired := hosts("10.0.0.1 a.lan")
+	assert.Equal(t, desired, Apply([]*Rule{rule}, desired, nil))
+
+	rule, err = NewRule("dns.hosts", Union, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, desired, Apply([]*Rule{rule}, desired, map[string]any{}))
+}
+
+func TestElementKeys(t *testing.T) {
+	assert.Equal(t, "nas.lan nas", elementKeys["dns.hosts"]("192.168.1.10 nas.lan nas"))
+	assert.Equal(t, "alias.lan", elementKeys["dns.cnameRecords"]("alias.lan,target.lan,300"))
+	assert.Equal(t, "aa:bb:cc:dd:ee:ff", elementKeys["dhcp.hosts"]("aa:bb:cc:dd:ee:ff,192.168.1.10,nas"))
+}
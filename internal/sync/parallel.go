@@ -0,0 +1,106 @@
+package sync
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+)
+
+// forEachReplica runs action for every replica using at most Parallelism concurrent workers.
+// In best effort mode replicas that already failed during this run are skipped and failures are recorded
+// instead of returned. Otherwise no further replicas are started once one fails.
+func (target *target) forEachReplica(action func(replica pihole.Client) error) error {
+	if target.run == nil || !target.run.bestEffort {
+		return errors.Join(target.runParallel(target.active(), action, true)...)
+	}
+
+	var replicas []pihole.Client
+	for _, replica := range target.active() {
+		if !target.run.failed(replica) {
+			replicas = append(replicas, replica)
+		}
+	}
+
+	for i, err := range target.runParallel(replicas, action, false) {
+		if err != nil {
+			target.run.failures[replicas[i]] = err
+		}
+	}
+
+	return nil
+}
+
+// active returns the replicas the current stage runs against.
+func (target *target) active() []pihole.Client {
+	if target.run != nil && target.run.replicas != nil {
+		return target.run.replicas
+	}
+	return target.Replicas
+}
+
+// runParallel returns the error of each replica by index.
+// When stopOnFailure is set replicas already in flight run to completion but no new ones are started.
+func (target *target) runParallel(replicas []pihole.Client, action func(replica pihole.Client) error, stopOnFailure bool) []error {
+	errs := make([]error, len(replicas))
+	done := make(chan int, len(replicas))
+	workers := make(chan struct{}, target.workers())
+
+	started := 0
+	failed := false
+	for i, replica := range replicas {
+		workers <- struct{}{}
+
+		// collect finished replicas so a failure stops the remaining ones from starting
+		for len(done) > 0 {
+			if errs[<-done] != nil {
+				failed = true
+			}
+			started--
+		}
+		if failed && stopOnFailure {
+			<-workers
+			break
+		}
+
+		started++
+		go func() {
+			defer func() {
+				done <- i
+				<-workers
+			}()
+
+			if err := action(replica); err != nil {
+				log.Warn().Str("replica", replica.String()).Err(err).Msg("Replica failed")
+				errs[i] = err
+			}
+		}()
+	}
+
+	for range started {
+		<-done
+	}
+
+	return errs
+}
+
+func (target *target) workers() int {
+	// a rolling update restarts one replica at a time
+	if target.run != nil && target.run.rolling != nil {
+		return 1
+	}
+	return max(target.Parallelism, 1)
+}
+
+// sortByReplica sorts items in the order of replicas, since replicas finish in any order when run in parallel.
+func sortByReplica[T any](replicas []pihole.Client, items []T, replica func(T) string) {
+	order := make(map[string]int, len(replicas))
+	for i, client := range replicas {
+		order[client.String()] = i
+	}
+	slices.SortFunc(items, func(a, b T) int {
+		return order[replica(a)] - order[replica(b)]
+	})
+}
@@ -0,0 +1,80 @@
+package sync
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+)
+
+func Test_target_forEachReplica_concurrent(t *testing.T) {
+	replica1 := piholemock.NewClient(t)
+	replica2 := piholemock.NewClient(t)
+
+	target := target{
+		Replicas:    []pihole.Client{replica1, replica2},
+		Parallelism: 2,
+	}
+
+	barrier := make(chan struct{})
+	err := target.forEachReplica(func(replica pihole.Client) error {
+		select {
+		case barrier <- struct{}{}:
+		case <-barrier:
+		case <-time.After(time.Second):
+			return errors.New("replicas did not run concurrently")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func Test_target_forEachReplica_errors(t *testing.T) {
+	replica1 := piholemock.NewClient(t)
+	replica2 := piholemock.NewClient(t)
+
+	target := target{
+		Replicas:    []pihole.Client{replica1, replica2},
+		Parallelism: 2,
+	}
+
+	replica1.EXPECT().String().Return("http://replica1")
+	replica2.EXPECT().String().Return("http://replica2")
+
+	err1 := errors.New("replica1 error")
+	err2 := errors.New("replica2 error")
+	err := target.forEachReplica(func(replica pihole.Client) error {
+		if replica == replica1 {
+			return err1
+		}
+		return err2
+	})
+
+	require.ErrorIs(t, err, err1)
+	require.ErrorIs(t, err, err2)
+}
+
+func Test_target_forEachReplica_stopsAfterFailure(t *testing.T) {
+	replica1 := piholemock.NewClient(t)
+	replica2 := piholemock.NewClient(t)
+
+	target := target{
+		Replicas: []pihole.Client{replica1, replica2},
+	}
+
+	replica1.EXPECT().String().Return("http://replica1")
+
+	var called []pihole.Client
+	err := target.forEachReplica(func(replica pihole.Client) error {
+		called = append(called, replica)
+		return errors.New("failed")
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, []pihole.Client{replica1}, called)
+}
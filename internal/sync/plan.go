@@ -0,0 +1,176 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/sync/checksum"
+	"github.com/lovelaze/nebula-sync/internal/sync/diff"
+)
+
+type Plan struct {
+	Replicas []ReplicaPlan
+}
+
+type ReplicaPlan struct {
+	Replica    string
+	Teleporter *model.PostTeleporterRequest
+	// Gravity lists the gravity entries that would change when they are synced as records instead of a teleporter
+	Gravity []GravityChange
+	Changes []diff.Change
+}
+
+func (target *target) Plan(ctx context.Context, conf *config.Sync) (*Plan, error) {
+	var plan *Plan
+
+	err := target.sync(ctx, conf, func() error {
+		var err error
+		plan, err = target.plan(ctx, conf)
+		return err
+	}, "plan")
+
+	return plan, err
+}
+
+func (target *target) plan(ctx context.Context, conf *config.Sync) (*Plan, error) {
+	gravitySettings, configSettings := conf.GravitySettings, conf.ConfigSettings
+	if conf.FullSync {
+		gravitySettings, configSettings = newFullSyncGravitySettings(), newFullSyncConfigSettings()
+	}
+
+	log.Info().Msg("Planning configs...")
+	configResponse, err := target.Primary.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := target.run != nil && target.run.records
+
+	var primaryRecords *gravityRecords
+	if records {
+		if primaryRecords, err = getGravityRecords(ctx, target.Primary, newFullSyncGravitySettings()); err != nil {
+			return nil, err
+		}
+	}
+
+	// the teleporter archive and gravity checksum are only needed to tell whether a sync would skip the import
+	var archive []byte
+	var gravity string
+	if target.checksums != nil && !records {
+		if archive, err = target.Primary.GetTeleporter(ctx); err != nil {
+			return nil, err
+		}
+		if gravity, err = target.gravityChecksum(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	plan := Plan{}
+	for _, replica := range target.Replicas {
+		if target.run != nil && target.run.failed(replica) {
+			continue
+		}
+
+		override := target.override(replica)
+		desired, err := target.transform(replica, createPatchConfigRequest(override.ApplyConfig(configSettings), configResponse).Config.Map())
+		if err != nil {
+			return nil, err
+		}
+
+		var teleporterRequest *model.PostTeleporterRequest
+		var gravityChanges []GravityChange
+		if records {
+			diff, err := target.gravityDiff(ctx, replica, gravitySettings, primaryRecords)
+			if err != nil {
+				return nil, err
+			}
+			gravityChanges = diff.changes()
+		} else if settings := override.ApplyGravity(gravitySettings); settings != nil {
+			teleporterRequest = createPostTeleporterRequest(settings)
+		}
+
+		teleporterUnchanged, configUnchanged, err := target.skipped(replica, archive, gravity, teleporterRequest, desired)
+		if err != nil {
+			return nil, err
+		}
+		if teleporterUnchanged {
+			teleporterRequest = nil
+		}
+
+		var changes []diff.Change
+		if !configUnchanged {
+			if changes, err = target.configChanges(ctx, replica, desired); err != nil {
+				return nil, err
+			}
+		}
+
+		plan.Replicas = append(plan.Replicas, ReplicaPlan{
+			Replica:    replica.String(),
+			Teleporter: teleporterRequest,
+			Gravity:    gravityChanges,
+			Changes:    changes,
+		})
+	}
+
+	return &plan, nil
+}
+
+// skipped reports whether a sync would skip the teleporter import and the config patch of replica, since it applied
+// them before.
+func (target *target) skipped(
+	replica pihole.Client,
+	archive []byte,
+	gravity string,
+	teleporterRequest *model.PostTeleporterRequest,
+	desired map[string]any,
+) (bool, bool, error) {
+	if target.checksums == nil {
+		return false, false, nil
+	}
+
+	teleporterSum, err := checksum.Teleporter(archive, gravity, teleporterRequest)
+	if err != nil {
+		return false, false, err
+	}
+	configSum, err := checksum.Of(desired)
+	if err != nil {
+		return false, false, err
+	}
+
+	return target.unchanged(replica, checksum.KindTeleporter, teleporterSum),
+		target.unchanged(replica, checksum.KindConfig, configSum), nil
+}
+
+func (plan *Plan) Log() {
+	for _, replica := range plan.Replicas {
+		logger := log.With().Str("replica", replica.Replica).Logger()
+
+		if replica.Teleporter != nil {
+			logger.Info().Any("import", replica.Teleporter).Msg("Teleporter would be imported")
+		}
+
+		for _, change := range replica.Gravity {
+			logger.Info().
+				Str("table", change.Table).
+				Str("action", change.Action).
+				Str("key", change.Key).
+				Msg("Gravity record would change")
+		}
+
+		if len(replica.Changes) == 0 {
+			logger.Info().Msg("Config already in sync")
+		}
+
+		for _, change := range replica.Changes {
+			logger.Info().
+				Str("key", change.Key).
+				Any("from", change.From).
+				Any("to", change.To).
+				Msg("Config would change")
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/sync/checksum"
+	"github.com/lovelaze/nebula-sync/internal/sync/diff"
+)
+
+func TestTarget_Plan(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	target := NewTarget(primary, []pihole.Client{replica}, 1, nil)
+
+	primaryConfig := emptyConfigResponse()
+	primaryConfig.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}, "interface": "eth0"}
+
+	replicaConfig := emptyConfigResponse()
+	replicaConfig.Config["dns"] = map[string]any{"upstreams": []any{"8.8.8.8"}, "interface": "eth0"}
+
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	replica.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(primaryConfig, nil)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(replicaConfig, nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	primary.EXPECT().HasSession().Return(true)
+
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	replica.EXPECT().HasSession().Return(true)
+	replica.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+
+	plan, err := target.Plan(context.Background(), &config.Sync{
+		FullSync: true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, plan.Replicas, 1)
+	assert.Equal(t, "http://replica", plan.Replicas[0].Replica)
+	assert.Equal(t, createPostTeleporterRequest(newFullSyncGravitySettings()), plan.Replicas[0].Teleporter)
+	assert.Equal(t, []diff.Change{
+		{Key: "dns.upstreams", Path: []string{"dns", "upstreams"}, From: []any{"8.8.8.8"}, To: []any{"1.1.1.1"}},
+	}, plan.Replicas[0].Changes)
+}
+
+func TestTarget_Plan_selective(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Primary:  primary,
+		Replicas: []pihole.Client{replica},
+	}
+
+	primaryConfig := &model.ConfigResponse{Config: map[string]any{
+		"dns": map[string]any{"upstreams": []any{"1.1.1.1"}, "interface": "eth1"},
+	}}
+	replicaConfig := &model.ConfigResponse{Config: map[string]any{
+		"dns": map[string]any{"upstreams": []any{"8.8.8.8"}, "interface": "eth0"},
+	}}
+
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(primaryConfig, nil)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(replicaConfig, nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	plan, err := target.plan(context.Background(), &config.Sync{
+		ConfigSettings: &config.ConfigSettings{
+			DNS:       config.NewConfigSetting(true, nil, []string{"interface"}),
+			DHCP:      config.NewConfigSetting(false, nil, nil),
+			NTP:       config.NewConfigSetting(false, nil, nil),
+			Resolver:  config.NewConfigSetting(false, nil, nil),
+			Database:  config.NewConfigSetting(false, nil, nil),
+			Webserver: config.NewConfigSetting(false, nil, nil),
+			Files:     config.NewConfigSetting(false, nil, nil),
+			Misc:      config.NewConfigSetting(false, nil, nil),
+			Debug:     config.NewConfigSetting(false, nil, nil),
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, plan.Replicas, 1)
+	assert.Nil(t, plan.Replicas[0].Teleporter)
+	assert.Equal(t, []diff.Change{
+		{Key: "dns.upstreams", Path: []string{"dns", "upstreams"}, From: []any{"8.8.8.8"}, To: []any{"1.1.1.1"}},
+	}, plan.Replicas[0].Changes)
+}
+
+func TestTarget_Plan_unchanged(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	checksums, err := checksum.NewStore("")
+	require.NoError(t, err)
+
+	target := target{
+		Primary:   primary,
+		Replicas:  []pihole.Client{replica},
+		checksums: checksums,
+	}
+
+	conf := &config.Sync{FullSync: true}
+	primary.EXPECT().GetConfig(mock.Anything).Return(emptyConfigResponse(), nil)
+	primary.EXPECT().GetTeleporter(mock.Anything).Return([]byte{}, nil)
+	expectGravityRecords(primary, primaryRecords())
+	replica.EXPECT().String().Return("http://replica")
+
+	gravitySum, err := primaryRecords().checksum()
+	require.NoError(t, err)
+	teleporterSum, err := checksum.Teleporter([]byte{}, gravitySum, createPostTeleporterRequest(newFullSyncGravitySettings()))
+	require.NoError(t, err)
+	configSum, err := checksum.Of(createPatchConfigRequest(newFullSyncConfigSettings(), emptyConfigResponse()).Config.Map())
+	require.NoError(t, err)
+	require.NoError(t, checksums.Set("http://replica", checksum.KindTeleporter, teleporterSum))
+	require.NoError(t, checksums.Set("http://replica", checksum.KindConfig, configSum))
+
+	plan, err := target.plan(context.Background(), conf)
+	require.NoError(t, err)
+
+	require.Len(t, plan.Replicas, 1)
+	assert.Nil(t, plan.Replicas[0].Teleporter)
+	assert.Empty(t, plan.Replicas[0].Changes)
+}
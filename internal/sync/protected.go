@@ -0,0 +1,34 @@
+package sync
+
+import (
+	"github.com/lovelaze/nebula-sync/internal/sync/filter"
+)
+
+// protectedKeys are never synced, regardless of include and exclude filters, because they identify the
+// instance or hold credentials. Syncing them could lock nebula-sync or users out of a replica.
+var protectedKeys = map[string][]string{
+	"webserver": {
+		"domain",
+		"acl",
+		"port",
+		"tls",
+		"paths",
+		"api.pwhash",
+		"api.password",
+		"api.app_pwhash",
+		"api.app_sudo",
+		"api.cli_pw",
+		"api.totp_secret",
+	},
+	"files": {
+		"pid",
+		"database",
+		"gravity",
+		"gravity_tmp",
+	},
+}
+
+// withoutProtectedKeys removes the protected keys of section from json. Keys already removed by a filter are ignored.
+func withoutProtectedKeys(section string, json map[string]any) map[string]any {
+	return filter.Remove(section, protectedKeys[section], json)
+}
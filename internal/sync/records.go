@@ -0,0 +1,628 @@
+package sync
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/sync/checksum"
+	"github.com/lovelaze/nebula-sync/internal/sync/retry"
+)
+
+// Gravity tables synced by the records engine, named like the teleporter entries they replace.
+const (
+	tableGroup      = "group"
+	tableAdlist     = "adlist"
+	tableDomainlist = "domainlist"
+	tableClient     = "client"
+)
+
+const (
+	actionAdd    = "add"
+	actionUpdate = "update"
+	actionDelete = "delete"
+)
+
+// defaultGroup is the ID of the group every Pi-hole has. It is matched by ID instead of name, since it can be renamed,
+// and is never added or deleted.
+const defaultGroup = 0
+
+// GravityChange is a gravity entry the records engine adds to, updates on or deletes from a replica.
+type GravityChange struct {
+	Table  string `json:"table"`
+	Action string `json:"action"`
+	Key    string `json:"key"`
+}
+
+// gravityRecords are the gravity entries of a Pi-hole. Tables that are not synced are left empty.
+type gravityRecords struct {
+	groups  []model.Group
+	lists   []model.List
+	domains []model.Domain
+	clients []model.Client
+}
+
+// recordDiff holds the entries of a table that differ between primary and replica.
+type recordDiff[T any] struct {
+	add []T
+	// update pairs the entry of the primary with the one of the replica it replaces
+	update [][2]T
+	remove []T
+}
+
+// gravityDiff holds the changes that bring the gravity entries of a replica in line with the primary.
+// Entries refer to groups by name, since group IDs differ between Pi-holes.
+type gravityDiff struct {
+	settings *config.GravitySettings
+	// primaryGroups and replicaGroups resolve the group IDs entries are assigned to
+	primaryGroups []model.Group
+	replicaGroups []model.Group
+
+	groups  recordDiff[model.Group]
+	lists   recordDiff[model.List]
+	domains recordDiff[model.Domain]
+	clients recordDiff[model.Client]
+}
+
+// syncRecords syncs the gravity entries of every replica by adding, updating and deleting them one by one through the
+// API. Unlike a teleporter import this keeps FTL running and, in additive mode, entries that only exist on a replica.
+func (target *target) syncRecords(ctx context.Context, gravitySettings *config.GravitySettings) error {
+	log.Info().Msg("Syncing gravity records...")
+	if gravitySettings != nil && gravitySettings.DHCPLeases {
+		log.Warn().Msg("DHCP leases are not synced by the records gravity engine")
+	}
+
+	primary, err := getGravityRecords(ctx, target.Primary, newFullSyncGravitySettings())
+	if err != nil {
+		return err
+	}
+	if target.run != nil {
+		target.run.adlists = adlistsChecksum(primary.lists)
+	}
+
+	return target.forEachReplica(func(replica pihole.Client) error {
+		diff, err := target.gravityDiff(ctx, replica, gravitySettings, primary)
+		if err != nil {
+			return err
+		}
+
+		changes := diff.changes()
+		if len(changes) == 0 {
+			log.Info().Str("replica", replica.String()).Msg("Gravity records already in sync")
+			return nil
+		}
+
+		target.modify(replica)
+		if err := diff.apply(ctx, replica); err != nil {
+			return err
+		}
+
+		logger := log.With().Str("replica", replica.String()).Logger()
+		for _, change := range changes {
+			logger.Debug().
+				Str("table", change.Table).
+				Str("action", change.Action).
+				Str("key", change.Key).
+				Msg("Gravity record synced")
+		}
+		logger.Info().
+			Int("added", diff.count(actionAdd)).
+			Int("updated", diff.count(actionUpdate)).
+			Int("deleted", diff.count(actionDelete)).
+			Msg("Gravity records synced")
+		return nil
+	})
+}
+
+// gravityDiff reads the gravity entries of replica and compares them to those of the primary.
+func (target *target) gravityDiff(
+	ctx context.Context,
+	replica pihole.Client,
+	gravitySettings *config.GravitySettings,
+	primary *gravityRecords,
+) (*gravityDiff, error) {
+	settings := target.override(replica).ApplyGravity(gravitySettings)
+	if settings == nil {
+		settings = newFullSyncGravitySettings()
+	}
+
+	var records *gravityRecords
+	if err := retry.Fixed(ctx, func() error {
+		var err error
+		records, err = getGravityRecords(ctx, replica, settings)
+		return err
+	}, retry.AttemptsGetGravity); err != nil {
+		return nil, fmt.Errorf("get replica gravity: %w", err)
+	}
+
+	// only the records engine can keep entries that exist on the replica alone
+	additive := target.run != nil && target.run.records && target.run.additive
+	return newGravityDiff(primary, records, settings, additive), nil
+}
+
+// getGravityRecords reads the tables of client synced with settings. Groups are always read to resolve group IDs.
+func getGravityRecords(ctx context.Context, client pihole.Client, settings *config.GravitySettings) (*gravityRecords, error) {
+	records := &gravityRecords{}
+
+	var err error
+	if records.groups, err = client.GetGroups(ctx); err != nil {
+		return nil, fmt.Errorf("get groups: %w", err)
+	}
+	if settings.Adlist {
+		if records.lists, err = client.GetLists(ctx); err != nil {
+			return nil, fmt.Errorf("get lists: %w", err)
+		}
+	}
+	if settings.Domainlist {
+		if records.domains, err = client.GetDomains(ctx, "", ""); err != nil {
+			return nil, fmt.Errorf("get domains: %w", err)
+		}
+	}
+	if settings.Client {
+		if records.clients, err = client.GetClients(ctx); err != nil {
+			return nil, fmt.Errorf("get clients: %w", err)
+		}
+	}
+
+	return records, nil
+}
+
+// checksum returns a checksum of the entries, leaving out the list statistics gravity updates whenever it runs and the
+// client names Pi-hole resolves.
+func (records *gravityRecords) checksum() (string, error) {
+	lists := make([]model.List, 0, len(records.lists))
+	for _, list := range records.lists {
+		list.DateUpdated, list.Number, list.InvalidDomains, list.ABPEntries, list.Status = 0, 0, 0, 0, 0
+		lists = append(lists, list)
+	}
+	// client names are resolved by Pi-hole
+	clients := make([]model.Client, 0, len(records.clients))
+	for _, client := range records.clients {
+		client.Name = ""
+		clients = append(clients, client)
+	}
+
+	return checksum.Of(struct {
+		Groups  []model.Group
+		Lists   []model.List
+		Domains []model.Domain
+		Clients []model.Client
+	}{records.groups, lists, records.domains, clients})
+}
+
+func newGravityDiff(primary, replica *gravityRecords, settings *config.GravitySettings, additive bool) *gravityDiff {
+	diff := &gravityDiff{settings: settings, primaryGroups: primary.groups, replicaGroups: replica.groups}
+
+	if settings.Group {
+		diff.groups = diffRecords(withoutDefaultGroup(primary.groups), withoutDefaultGroup(replica.groups), groupKey,
+			func(p, r model.Group) bool {
+				return p.Comment == r.Comment && p.Enabled == r.Enabled
+			}, additive)
+	}
+
+	// groups added by this sync count as existing on the replica, so entries can already be assigned to them
+	names := newGroupNames(primary.groups, replica.groups, diff.groups.add)
+
+	if settings.Adlist {
+		diff.lists = diffRecords(primary.lists, replica.lists, listKey, func(p, r model.List) bool {
+			return p.Type == r.Type && p.Comment == r.Comment && p.Enabled == r.Enabled &&
+				(!settings.AdlistByGroup || names.equal(p.Groups, r.Groups))
+		}, additive)
+	}
+
+	if settings.Domainlist {
+		diff.domains = diffRecords(primary.domains, replica.domains, domainKey, func(p, r model.Domain) bool {
+			return p.Comment == r.Comment && p.Enabled == r.Enabled &&
+				(!settings.DomainlistByGroup || names.equal(p.Groups, r.Groups))
+		}, additive)
+	}
+
+	if settings.Client {
+		diff.clients = diffRecords(primary.clients, replica.clients, clientKey, func(p, r model.Client) bool {
+			return p.Comment == r.Comment && (!settings.ClientByGroup || names.equal(p.Groups, r.Groups))
+		}, additive)
+	}
+
+	return diff
+}
+
+// diffRecords matches the entries of primary and replica by key. Entries only on the replica are kept if additive.
+func diffRecords[T any](
+	primary, replica []T,
+	key func(T) string,
+	equal func(primary, replica T) bool,
+	additive bool,
+) recordDiff[T] {
+	replicaEntries := make(map[string]T, len(replica))
+	for _, entry := range replica {
+		replicaEntries[key(entry)] = entry
+	}
+
+	diff := recordDiff[T]{}
+	primaryKeys := make(map[string]bool, len(primary))
+	for _, entry := range primary {
+		primaryKeys[key(entry)] = true
+
+		replicaEntry, exists := replicaEntries[key(entry)]
+		switch {
+		case !exists:
+			diff.add = append(diff.add, entry)
+		case !equal(entry, replicaEntry):
+			diff.update = append(diff.update, [2]T{entry, replicaEntry})
+		}
+	}
+
+	if !additive {
+		for _, entry := range replica {
+			if !primaryKeys[key(entry)] {
+				diff.remove = append(diff.remove, entry)
+			}
+		}
+	}
+
+	return diff
+}
+
+func (diff recordDiff[T]) changes(table string, key func(T) string) []GravityChange {
+	changes := make([]GravityChange, 0, len(diff.add)+len(diff.update)+len(diff.remove))
+	for _, entry := range diff.add {
+		changes = append(changes, GravityChange{Table: table, Action: actionAdd, Key: key(entry)})
+	}
+	for _, entries := range diff.update {
+		changes = append(changes, GravityChange{Table: table, Action: actionUpdate, Key: key(entries[0])})
+	}
+	for _, entry := range diff.remove {
+		changes = append(changes, GravityChange{Table: table, Action: actionDelete, Key: key(entry)})
+	}
+	return changes
+}
+
+// changes returns every change of the diff, in the order they are applied.
+func (diff *gravityDiff) changes() []GravityChange {
+	return slices.Concat(
+		diff.groups.changes(tableGroup, groupKey),
+		diff.lists.changes(tableAdlist, listKey),
+		diff.domains.changes(tableDomainlist, domainKey),
+		diff.clients.changes(tableClient, clientKey),
+	)
+}
+
+func (diff *gravityDiff) count(action string) int {
+	count := 0
+	for _, change := range diff.changes() {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// tables returns the names of the tables with at least one change.
+func (diff *gravityDiff) tables() []string {
+	var tables []string
+	for _, change := range diff.changes() {
+		if !slices.Contains(tables, change.Table) {
+			tables = append(tables, change.Table)
+		}
+	}
+	slices.Sort(tables)
+	return tables
+}
+
+// apply writes the diff to replica. Groups are added first so entries can be assigned to them, and deleted last.
+func (diff *gravityDiff) apply(ctx context.Context, replica pihole.Client) error {
+	for _, group := range diff.groups.add {
+		request := &model.GroupRequest{Name: group.Name, Comment: group.Comment, Enabled: group.Enabled}
+		if err := replica.PostGroup(ctx, request); err != nil {
+			return fmt.Errorf("add group %s: %w", group.Name, err)
+		}
+	}
+	for _, groups := range diff.groups.update {
+		group := groups[0]
+		request := &model.GroupRequest{Name: group.Name, Comment: group.Comment, Enabled: group.Enabled}
+		if err := replica.PutGroup(ctx, group.Name, request); err != nil {
+			return fmt.Errorf("update group %s: %w", group.Name, err)
+		}
+	}
+
+	replicaGroups := diff.replicaGroups
+	if len(diff.groups.add) > 0 {
+		var err error
+		if replicaGroups, err = replica.GetGroups(ctx); err != nil {
+			return fmt.Errorf("get groups: %w", err)
+		}
+	}
+	ids := newGroupIDs(diff.primaryGroups, replicaGroups)
+
+	if err := diff.applyLists(ctx, replica, ids); err != nil {
+		return err
+	}
+	if err := diff.applyDomains(ctx, replica, ids); err != nil {
+		return err
+	}
+	if err := diff.applyClients(ctx, replica, ids); err != nil {
+		return err
+	}
+
+	if len(diff.groups.remove) > 0 {
+		names := make([]string, 0, len(diff.groups.remove))
+		for _, group := range diff.groups.remove {
+			names = append(names, group.Name)
+		}
+		if err := replica.DeleteGroups(ctx, names); err != nil {
+			return fmt.Errorf("delete groups: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (diff *gravityDiff) applyLists(ctx context.Context, replica pihole.Client, ids *groupIDs) error {
+	byGroup := diff.settings.AdlistByGroup
+
+	for _, list := range diff.lists.add {
+		request := &model.ListRequest{
+			Address: list.Address,
+			Comment: list.Comment,
+			Groups:  ids.assign(list.Groups, nil, byGroup),
+			Enabled: list.Enabled,
+		}
+		if err := replica.PostList(ctx, list.Type, request); err != nil {
+			return fmt.Errorf("add list %s: %w", list.Address, err)
+		}
+	}
+
+	for _, lists := range diff.lists.update {
+		list, current := lists[0], lists[1]
+		request := &model.ListRequest{
+			Type:    list.Type,
+			Comment: list.Comment,
+			Groups:  ids.assign(list.Groups, current.Groups, byGroup),
+			Enabled: list.Enabled,
+		}
+		if err := replica.PutList(ctx, current.Address, current.Type, request); err != nil {
+			return fmt.Errorf("update list %s: %w", list.Address, err)
+		}
+	}
+
+	if len(diff.lists.remove) > 0 {
+		items := make([]model.BatchDeleteItem, 0, len(diff.lists.remove))
+		for _, list := range diff.lists.remove {
+			items = append(items, model.BatchDeleteItem{Item: list.Address, Type: list.Type})
+		}
+		if err := replica.DeleteLists(ctx, items); err != nil {
+			return fmt.Errorf("delete lists: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (diff *gravityDiff) applyDomains(ctx context.Context, replica pihole.Client, ids *groupIDs) error {
+	byGroup := diff.settings.DomainlistByGroup
+
+	for _, domain := range diff.domains.add {
+		request := &model.DomainRequest{
+			Domain:  domain.Domain,
+			Comment: domain.Comment,
+			Groups:  ids.assign(domain.Groups, nil, byGroup),
+			Enabled: domain.Enabled,
+		}
+		if err := replica.PostDomain(ctx, domain.Type, domain.Kind, request); err != nil {
+			return fmt.Errorf("add domain %s: %w", domain.Domain, err)
+		}
+	}
+
+	for _, domains := range diff.domains.update {
+		domain, current := domains[0], domains[1]
+		request := &model.DomainRequest{
+			Comment: domain.Comment,
+			Groups:  ids.assign(domain.Groups, current.Groups, byGroup),
+			Enabled: domain.Enabled,
+		}
+		if err := replica.PutDomain(ctx, current.Domain, current.Type, current.Kind, request); err != nil {
+			return fmt.Errorf("update domain %s: %w", domain.Domain, err)
+		}
+	}
+
+	if len(diff.domains.remove) > 0 {
+		items := make([]model.BatchDeleteItem, 0, len(diff.domains.remove))
+		for _, domain := range diff.domains.remove {
+			items = append(items, model.BatchDeleteItem{Item: domain.Domain, Type: domain.Type, Kind: domain.Kind})
+		}
+		if err := replica.DeleteDomains(ctx, items); err != nil {
+			return fmt.Errorf("delete domains: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (diff *gravityDiff) applyClients(ctx context.Context, replica pihole.Client, ids *groupIDs) error {
+	byGroup := diff.settings.ClientByGroup
+
+	for _, client := range diff.clients.add {
+		request := &model.ClientRequest{
+			Client:  client.Client,
+			Comment: client.Comment,
+			Groups:  ids.assign(client.Groups, nil, byGroup),
+		}
+		if err := replica.PostClient(ctx, request); err != nil {
+			return fmt.Errorf("add client %s: %w", client.Client, err)
+		}
+	}
+
+	for _, clients := range diff.clients.update {
+		client, current := clients[0], clients[1]
+		request := &model.ClientRequest{
+			Comment: client.Comment,
+			Groups:  ids.assign(client.Groups, current.Groups, byGroup),
+		}
+		if err := replica.PutClient(ctx, current.Client, request); err != nil {
+			return fmt.Errorf("update client %s: %w", client.Client, err)
+		}
+	}
+
+	if len(diff.clients.remove) > 0 {
+		clients := make([]string, 0, len(diff.clients.remove))
+		for _, client := range diff.clients.remove {
+			clients = append(clients, client.Client)
+		}
+		if err := replica.DeleteClients(ctx, clients); err != nil {
+			return fmt.Errorf("delete clients: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// groupNames compares group assignments of the primary and a replica by group name.
+type groupNames struct {
+	primary map[int]string
+	replica map[int]string
+	exists  map[string]bool
+}
+
+func newGroupNames(primary, replica, added []model.Group) *groupNames {
+	names := &groupNames{
+		primary: make(map[int]string, len(primary)),
+		replica: make(map[int]string, len(replica)),
+		exists:  make(map[string]bool, len(replica)),
+	}
+	for _, group := range primary {
+		names.primary[group.ID] = groupKey(group)
+	}
+	for _, group := range replica {
+		names.replica[group.ID] = groupKey(group)
+		names.exists[groupKey(group)] = true
+	}
+	for _, group := range added {
+		names.exists[groupKey(group)] = true
+	}
+	return names
+}
+
+// equal reports whether the primary and replica group IDs refer to the same groups. Groups of the primary missing on
+// the replica are ignored, as they cannot be assigned.
+func (names *groupNames) equal(primary, replica []int) bool {
+	var primaryNames, replicaNames []string
+	for _, id := range primary {
+		if name, ok := names.primary[id]; ok && names.exists[name] {
+			primaryNames = append(primaryNames, name)
+		}
+	}
+	for _, id := range replica {
+		replicaNames = append(replicaNames, names.replica[id])
+	}
+
+	slices.Sort(primaryNames)
+	slices.Sort(replicaNames)
+	return slices.Equal(primaryNames, replicaNames)
+}
+
+// groupIDs translates group assignments of the primary to the group IDs of a replica.
+type groupIDs struct {
+	primary map[int]string
+	replica map[string]int
+}
+
+func newGroupIDs(primary, replica []model.Group) *groupIDs {
+	ids := &groupIDs{
+		primary: make(map[int]string, len(primary)),
+		replica: make(map[string]int, len(replica)),
+	}
+	for _, group := range primary {
+		ids.primary[group.ID] = groupKey(group)
+	}
+	for _, group := range replica {
+		ids.replica[groupKey(group)] = group.ID
+	}
+	return ids
+}
+
+// assign returns the replica group IDs for the primary group IDs if byGroup is set. Otherwise the current assignment
+// of the replica is kept, and entries added to the replica are assigned to the default group.
+func (ids *groupIDs) assign(primary, current []int, byGroup bool) []int {
+	if !byGroup {
+		if current == nil {
+			return []int{defaultGroup}
+		}
+		return current
+	}
+
+	assigned := make([]int, 0, len(primary))
+	for _, name := range ids.names(primary) {
+		if id, ok := ids.replica[name]; ok {
+			assigned = append(assigned, id)
+		} else {
+			log.Warn().Str("group", name).Msg("Group does not exist on replica, skipping assignment")
+		}
+	}
+	slices.Sort(assigned)
+	return assigned
+}
+
+func (ids *groupIDs) names(primary []int) []string {
+	names := make([]string, 0, len(primary))
+	for _, id := range primary {
+		if name, ok := ids.primary[id]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// groupKey is the name of group, or empty for the default group.
+func groupKey(group model.Group) string {
+	if group.ID == defaultGroup {
+		return ""
+	}
+	return group.Name
+}
+
+func listKey(list model.List) string {
+	return list.Address
+}
+
+func domainKey(domain model.Domain) string {
+	return domain.Type + "/" + domain.Kind + "/" + domain.Domain
+}
+
+func clientKey(client model.Client) string {
+	return client.Client
+}
+
+func withoutDefaultGroup(groups []model.Group) []model.Group {
+	return slices.DeleteFunc(slices.Clone(groups), func(group model.Group) bool {
+		return group.ID == defaultGroup
+	})
+}
+
+// adlistsChecksum returns a checksum of the lists gravity downloads, empty if it cannot be computed.
+func adlistsChecksum(lists []model.List) string {
+	type adlist struct {
+		Address string
+		Type    string
+		Enabled bool
+	}
+
+	adlists := make([]adlist, 0, len(lists))
+	for _, list := range lists {
+		adlists = append(adlists, adlist{Address: list.Address, Type: list.Type, Enabled: list.Enabled})
+	}
+	slices.SortFunc(adlists, func(a, b adlist) int {
+		return cmp.Or(cmp.Compare(a.Address, b.Address), cmp.Compare(a.Type, b.Type))
+	})
+
+	sum, err := checksum.Of(adlists)
+	if err != nil {
+		return ""
+	}
+	return sum
+}
@@ -0,0 +1,205 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+)
+
+func primaryRecords() *gravityRecords {
+	return &gravityRecords{
+		groups: []model.Group{
+			{ID: 0, Name: "Default", Enabled: true},
+			{ID: 1, Name: "kids", Enabled: true},
+			{ID: 2, Name: "iot", Enabled: true},
+		},
+		lists: []model.List{
+			{Address: "https://example.com/ads.txt", Type: model.ListTypeBlock, Groups: []int{0, 1}, Enabled: true},
+			{Address: "https://example.com/new.txt", Type: model.ListTypeBlock, Groups: []int{2}, Enabled: true},
+		},
+		domains: []model.Domain{
+			{Domain: "ads.example.com", Type: model.DomainTypeDeny, Kind: model.DomainKindExact, Enabled: true},
+		},
+		clients: []model.Client{
+			{Client: "192.168.1.10", Comment: "tablet", Groups: []int{1}},
+		},
+	}
+}
+
+func replicaRecords() *gravityRecords {
+	return &gravityRecords{
+		groups: []model.Group{
+			{ID: 0, Name: "Default", Enabled: true},
+			{ID: 5, Name: "kids", Enabled: true},
+			{ID: 6, Name: "guests", Enabled: true},
+		},
+		lists: []model.List{
+			{Address: "https://example.com/ads.txt", Type: model.ListTypeBlock, Groups: []int{5, 0}, Enabled: true},
+			{Address: "https://example.com/local.txt", Type: model.ListTypeBlock, Groups: []int{0}, Enabled: true},
+		},
+		domains: []model.Domain{
+			{Domain: "ads.example.com", Type: model.DomainTypeDeny, Kind: model.DomainKindExact, Enabled: false},
+			{Domain: "ads.example.com", Type: model.DomainTypeAllow, Kind: model.DomainKindExact, Enabled: true},
+		},
+		clients: []model.Client{
+			{Client: "192.168.1.10", Comment: "tablet", Groups: []int{5}},
+		},
+	}
+}
+
+func Test_newGravityDiff(t *testing.T) {
+	diff := newGravityDiff(primaryRecords(), replicaRecords(), newFullSyncGravitySettings(), false)
+
+	assert.Equal(t, []GravityChange{
+		{Table: tableGroup, Action: actionAdd, Key: "iot"},
+		{Table: tableGroup, Action: actionDelete, Key: "guests"},
+		{Table: tableAdlist, Action: actionAdd, Key: "https://example.com/new.txt"},
+		{Table: tableAdlist, Action: actionDelete, Key: "https://example.com/local.txt"},
+		{Table: tableDomainlist, Action: actionUpdate, Key: "deny/exact/ads.example.com"},
+		{Table: tableDomainlist, Action: actionDelete, Key: "allow/exact/ads.example.com"},
+	}, diff.changes())
+	assert.Equal(t, []string{tableAdlist, tableDomainlist, tableGroup}, diff.tables())
+}
+
+func Test_newGravityDiff_additive(t *testing.T) {
+	diff := newGravityDiff(primaryRecords(), replicaRecords(), newFullSyncGravitySettings(), true)
+
+	assert.Equal(t, []GravityChange{
+		{Table: tableGroup, Action: actionAdd, Key: "iot"},
+		{Table: tableAdlist, Action: actionAdd, Key: "https://example.com/new.txt"},
+		{Table: tableDomainlist, Action: actionUpdate, Key: "deny/exact/ads.example.com"},
+	}, diff.changes())
+}
+
+func Test_newGravityDiff_byGroup(t *testing.T) {
+	replica := replicaRecords()
+	replica.clients[0].Groups = []int{0}
+
+	diff := newGravityDiff(primaryRecords(), replica, &config.GravitySettings{Client: true}, false)
+	assert.Empty(t, diff.changes())
+
+	diff = newGravityDiff(primaryRecords(), replica, &config.GravitySettings{Client: true, ClientByGroup: true}, false)
+	assert.Equal(t, []GravityChange{{Table: tableClient, Action: actionUpdate, Key: "192.168.1.10"}}, diff.changes())
+}
+
+func Test_groupIDs_assign(t *testing.T) {
+	ids := newGroupIDs(primaryRecords().groups, replicaRecords().groups)
+
+	assert.Equal(t, []int{0, 5}, ids.assign([]int{1, 0, 2}, []int{6}, true))
+	assert.Equal(t, []int{6}, ids.assign([]int{1}, []int{6}, false))
+	assert.Equal(t, []int{defaultGroup}, ids.assign([]int{1}, nil, false))
+}
+
+func Test_target_syncRecords(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Primary:  primary,
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{GravityEngine: config.GravityEngineRecords}),
+	}
+
+	primary.EXPECT().GetGroups(mock.Anything).Return(primaryRecords().groups, nil)
+	primary.EXPECT().GetLists(mock.Anything).Return(primaryRecords().lists, nil)
+	primary.EXPECT().GetDomains(mock.Anything, "", "").Return(primaryRecords().domains, nil)
+	primary.EXPECT().GetClients(mock.Anything).Return(primaryRecords().clients, nil)
+
+	replica.EXPECT().GetGroups(mock.Anything).Once().Return(replicaRecords().groups, nil)
+	replica.EXPECT().GetLists(mock.Anything).Return(replicaRecords().lists, nil)
+	replica.EXPECT().GetDomains(mock.Anything, "", "").Return(replicaRecords().domains, nil)
+	replica.EXPECT().GetClients(mock.Anything).Return(replicaRecords().clients, nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	addGroup := replica.EXPECT().PostGroup(mock.Anything, &model.GroupRequest{Name: "iot", Enabled: true}).Once().Return(nil)
+	replicaGroups := append(replicaRecords().groups, model.Group{ID: 7, Name: "iot"})
+	replica.EXPECT().GetGroups(mock.Anything).Once().Return(replicaGroups, nil).NotBefore(addGroup)
+	replica.EXPECT().PostList(mock.Anything, model.ListTypeBlock, &model.ListRequest{
+		Address: "https://example.com/new.txt",
+		Groups:  []int{7},
+		Enabled: true,
+	}).Once().Return(nil)
+	replica.EXPECT().DeleteLists(mock.Anything, []model.BatchDeleteItem{
+		{Item: "https://example.com/local.txt", Type: model.ListTypeBlock},
+	}).Once().Return(nil)
+	replica.EXPECT().
+		PutDomain(mock.Anything, "ads.example.com", model.DomainTypeDeny, model.DomainKindExact, &model.DomainRequest{
+			Groups:  []int{},
+			Enabled: true,
+		}).
+		Once().Return(nil)
+	deleteDomains := replica.EXPECT().DeleteDomains(mock.Anything, []model.BatchDeleteItem{
+		{Item: "ads.example.com", Type: model.DomainTypeAllow, Kind: model.DomainKindExact},
+	}).Once().Return(nil)
+	replica.EXPECT().DeleteGroups(mock.Anything, []string{"guests"}).Once().Return(nil).NotBefore(deleteDomains)
+
+	require.NoError(t, target.syncRecords(context.Background(), newFullSyncGravitySettings()))
+	assert.NotEmpty(t, target.run.adlists)
+	assert.True(t, target.run.modified[replica])
+}
+
+func Test_target_syncRecords_inSync(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Primary:  primary,
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{GravityEngine: config.GravityEngineRecords}),
+	}
+
+	groups := []model.Group{{ID: 0, Name: "Default", Enabled: true}}
+	primary.EXPECT().GetGroups(mock.Anything).Return(groups, nil)
+	primary.EXPECT().GetLists(mock.Anything).Return(nil, nil)
+	primary.EXPECT().GetDomains(mock.Anything, "", "").Return(nil, nil)
+	primary.EXPECT().GetClients(mock.Anything).Return(nil, nil)
+
+	replica.EXPECT().GetGroups(mock.Anything).Once().Return(groups, nil)
+	replica.EXPECT().GetLists(mock.Anything).Once().Return(nil, nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	require.NoError(t, target.syncRecords(context.Background(), &config.GravitySettings{Group: true, Adlist: true}))
+	assert.False(t, target.run.modified[replica])
+}
+
+func Test_target_plan_records(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Primary:  primary,
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{GravityEngine: config.GravityEngineRecords, GravityAdditive: true}),
+	}
+
+	primary.EXPECT().GetConfig(mock.Anything).Return(emptyConfigResponse(), nil)
+	primary.EXPECT().GetGroups(mock.Anything).Return(primaryRecords().groups, nil)
+	primary.EXPECT().GetLists(mock.Anything).Return(primaryRecords().lists, nil)
+	primary.EXPECT().GetDomains(mock.Anything, "", "").Return(primaryRecords().domains, nil)
+	primary.EXPECT().GetClients(mock.Anything).Return(primaryRecords().clients, nil)
+
+	replica.EXPECT().GetConfig(mock.Anything).Return(emptyConfigResponse(), nil)
+	replica.EXPECT().GetGroups(mock.Anything).Return(replicaRecords().groups, nil)
+	replica.EXPECT().GetLists(mock.Anything).Return(replicaRecords().lists, nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	plan, err := target.plan(context.Background(), &config.Sync{
+		GravitySettings: &config.GravitySettings{Adlist: true},
+		ConfigSettings:  newFullSyncConfigSettings(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, plan.Replicas, 1)
+	assert.Nil(t, plan.Replicas[0].Teleporter)
+	assert.Equal(t, []GravityChange{
+		{Table: tableAdlist, Action: actionAdd, Key: "https://example.com/new.txt"},
+	}, plan.Replicas[0].Gravity)
+}
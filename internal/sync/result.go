@@ -0,0 +1,161 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	gosync "sync"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/sync/merge"
+	"github.com/lovelaze/nebula-sync/internal/sync/transform"
+)
+
+type ReplicaOutcome struct {
+	Replica    string `json:"replica"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+	RolledBack bool   `json:"rolledBack,omitempty"`
+}
+
+// ReplicasError is returned by a best effort sync when one or more replicas failed.
+type ReplicasError struct {
+	Outcomes []ReplicaOutcome
+	errs     []error
+}
+
+func (e *ReplicasError) Error() string {
+	return fmt.Sprintf("%d of %d replicas synced: %v", e.Synced(), len(e.Outcomes), errors.Join(e.errs...))
+}
+
+func (e *ReplicasError) Unwrap() []error {
+	return e.errs
+}
+
+func (e *ReplicasError) Synced() int {
+	synced := 0
+	for _, outcome := range e.Outcomes {
+		if outcome.Success {
+			synced++
+		}
+	}
+	return synced
+}
+
+// Partial reports whether at least one replica was synced successfully.
+func (e *ReplicasError) Partial() bool {
+	return e.Synced() > 0
+}
+
+// run holds the state of a single sync run.
+type run struct {
+	bestEffort bool
+	force      bool
+	// keepSessions reuses the sessions of previous runs and keeps them for the next one
+	keepSessions bool
+	failures     map[pihole.Client]error
+
+	rolling *rolling
+
+	// replicas limits the stages to a subset of the replicas, e.g. the canary
+	replicas []pihole.Client
+	// overrides holds the settings override of each replica, in the same order as target.Replicas
+	overrides      []*config.ReplicaOverride
+	transforms     []*transform.Rule
+	merges         []*merge.Rule
+	primaryGravity bool
+	// adlists is the checksum of the primary's adlists, empty if unknown
+	adlists string
+	// records syncs gravity entries one by one instead of importing teleporters, additive keeps replica-only entries
+	records  bool
+	additive bool
+
+	// mu guards the per replica state written by concurrent stages
+	mu         gosync.Mutex
+	snapshots  map[pihole.Client][]byte
+	desired    map[pihole.Client]map[string]any
+	modified   map[pihole.Client]bool
+	rolledBack map[pihole.Client]bool
+	// compatibilities holds the compatibility of each replica with the primary, detected at the start of the run
+	compatibilities map[pihole.Client]string
+}
+
+func newRun(conf *config.Sync) *run {
+	var rollingUpdate *rolling
+	if conf.Rolling {
+		rollingUpdate = &rolling{
+			delay:    conf.RollingDelay,
+			timeout:  conf.RollingTimeout,
+			dnsQuery: conf.RollingDNSQuery,
+		}
+	}
+
+	return &run{
+		rolling:      rollingUpdate,
+		bestEffort:   conf.BestEffort,
+		force:        conf.ForceSync,
+		keepSessions: conf.KeepSessions,
+		overrides:    conf.ReplicaOverrides,
+		transforms:   conf.TransformRules,
+		merges:       conf.MergeRules,
+		records:      conf.GravityEngine == config.GravityEngineRecords,
+		additive:     conf.GravityAdditive,
+		failures:     make(map[pihole.Client]error),
+		snapshots:    make(map[pihole.Client][]byte),
+		desired:      make(map[pihole.Client]map[string]any),
+		modified:     make(map[pihole.Client]bool),
+		rolledBack:   make(map[pihole.Client]bool),
+
+		compatibilities: make(map[pihole.Client]string),
+	}
+}
+
+func (r *run) failed(replica pihole.Client) bool {
+	_, failed := r.failures[replica]
+	return failed
+}
+
+func (r *run) err(replicas []pihole.Client) error {
+	if len(r.failures) == 0 {
+		return nil
+	}
+
+	replicasError := ReplicasError{}
+	for _, replica := range replicas {
+		outcome := ReplicaOutcome{Replica: replica.String(), Success: true}
+		if err, failed := r.failures[replica]; failed {
+			outcome.Success = false
+			outcome.Error = err.Error()
+			outcome.RolledBack = r.rolledBack[replica]
+			replicasError.errs = append(replicasError.errs, err)
+		}
+		replicasError.Outcomes = append(replicasError.Outcomes, outcome)
+	}
+
+	return &replicasError
+}
+
+func (r *run) setDesired(replica pihole.Client, desired map[string]any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.desired[replica] = desired
+}
+
+func (r *run) desiredConfig(replica pihole.Client) map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.desired[replica]
+}
+
+func (r *run) setCompatibility(replica pihole.Client, compatibility string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.compatibilities[replica] = compatibility
+}
+
+// compatibility returns the compatibility of replica with the primary, empty if it was not detected.
+func (r *run) compatibility(replica pihole.Client) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.compatibilities[replica]
+}
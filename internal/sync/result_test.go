@@ -0,0 +1,79 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/sync/retry"
+)
+
+func TestTarget_SelectiveSync_bestEffort(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	failing := piholemock.NewClient(t)
+	healthy := piholemock.NewClient(t)
+
+	target := NewTarget(primary, []pihole.Client{failing, healthy}, 1, nil)
+
+	teleporterErr := errors.New("teleporter error")
+
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	failing.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	healthy.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	failing.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	healthy.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+
+	primary.EXPECT().GetTeleporter(mock.Anything).Once().Return([]byte{}, nil)
+	failing.EXPECT().PostTeleporter(mock.Anything, mock.Anything, mock.Anything).Times(retry.AttemptsPostTeleporter).Return(teleporterErr)
+	healthy.EXPECT().PostTeleporter(mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	healthy.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+
+	primary.EXPECT().HasSession().Return(true)
+
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	failing.EXPECT().HasSession().Return(true)
+	failing.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	healthy.EXPECT().HasSession().Return(true)
+	healthy.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+
+	failing.EXPECT().String().Return("http://failing")
+	healthy.EXPECT().String().Return("http://healthy")
+
+	err := target.SelectiveSync(context.Background(), &config.Sync{
+		BestEffort:      true,
+		GravitySettings: &config.GravitySettings{},
+		ConfigSettings:  disabledConfigSettings(),
+	})
+
+	var replicasError *ReplicasError
+	require.ErrorAs(t, err, &replicasError)
+	require.ErrorIs(t, err, teleporterErr)
+	assert.True(t, replicasError.Partial())
+	assert.Equal(t, 1, replicasError.Synced())
+	assert.Equal(t, []ReplicaOutcome{
+		{Replica: "http://failing", Success: false, Error: "teleporter error"},
+		{Replica: "http://healthy", Success: true},
+	}, replicasError.Outcomes)
+}
+
+func TestReplicasError(t *testing.T) {
+	err := &ReplicasError{Outcomes: []ReplicaOutcome{
+		{Replica: "http://replica1", Success: false},
+		{Replica: "http://replica2", Success: false},
+	}}
+
+	assert.False(t, err.Partial())
+	assert.Equal(t, 0, err.Synced())
+	assert.Contains(t, err.Error(), "0 of 2 replicas synced")
+}
@@ -1,6 +1,7 @@
 package retry
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -13,9 +14,13 @@ import (
 const (
 	AttemptsPostTeleporter = 5
 	AttemptsPatchConfig    = 5
+	AttemptsGetConfig      = 3
+	AttemptsGetTeleporter  = 3
 	AttemptsPostRunGravity = 5
 	AttemptsPostAuth       = 3
 	AttemptsDeleteSession  = 3
+	AttemptsGetGravity     = 3
+	AttemptsGetVersion     = 3
 )
 
 var delay time.Duration
@@ -24,11 +29,17 @@ func Init(clientConfig *config.Client) {
 	delay = time.Duration(clientConfig.RetryDelay) * time.Second
 }
 
-func Fixed(retryFunc func() error, attempts uint) error {
+// Fixed calls retryFunc until it succeeds, up to attempts times with a fixed delay in between.
+// It stops retrying once ctx is done.
+func Fixed(ctx context.Context, retryFunc func() error, attempts uint) error {
 	return retry.Do(
 		func() error {
 			return retryFunc()
 		},
+		retry.Context(ctx),
+		retry.RetryIf(func(error) bool {
+			return ctx.Err() == nil
+		}),
 		retry.Attempts(attempts),
 		retry.Delay(delay),
 		retry.LastErrorOnly(true),
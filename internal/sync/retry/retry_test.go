@@ -1,6 +1,7 @@
 package retry
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -23,7 +24,7 @@ func TestWithRetry_DelayBetweenRetries(t *testing.T) {
 
 	counter := 0
 	start := time.Now()
-	err := Fixed(func() error {
+	err := Fixed(context.Background(), func() error {
 		counter++
 		if counter < 3 {
 			return errors.New("test error")
@@ -47,7 +48,7 @@ func TestWithRetry_NoRetriesOnImmediateSuccess(t *testing.T) {
 	})
 
 	counter := 0
-	err := Fixed(func() error {
+	err := Fixed(context.Background(), func() error {
 		counter++
 		return nil
 	}, 5) // 5 attempts, 2-second delay
@@ -65,7 +66,7 @@ func TestWithRetry_SuccessAfterRetries(t *testing.T) {
 	})
 
 	counter := 0
-	err := Fixed(func() error {
+	err := Fixed(context.Background(), func() error {
 		counter++
 		if counter < 2 {
 			return errors.New("test error")
@@ -86,7 +87,7 @@ func TestWithRetry_MaxAttemptsFailure(t *testing.T) {
 	})
 
 	counter := 0
-	err := Fixed(func() error {
+	err := Fixed(context.Background(), func() error {
 		counter++
 		return errors.New("test error")
 	}, 3) // 3 attempts, 1-second delay
@@ -94,3 +95,27 @@ func TestWithRetry_MaxAttemptsFailure(t *testing.T) {
 	require.Error(t, err, "Expected an error after max attempts")
 	assert.Equal(t, 3, counter, "Expected function to be retried 3 times")
 }
+
+// Test that retries stop once the context is done.
+func TestWithRetry_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	Init(&config.Client{
+		RetryDelay: 1,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	failure := errors.New("test error")
+
+	counter := 0
+	start := time.Now()
+	err := Fixed(ctx, func() error {
+		counter++
+		cancel()
+		return failure
+	}, 3)
+
+	require.ErrorIs(t, err, failure, "Expected the error of the last attempt")
+	assert.Equal(t, 1, counter, "Expected no retries after the context is done")
+	assert.Less(t, time.Since(start).Seconds(), 1.0, "Expected no delay after the context is done")
+}
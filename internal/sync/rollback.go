@@ -0,0 +1,150 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/sync/retry"
+)
+
+// rollbackTimeout limits the rollback, which runs on even if the sync was cancelled or timed out.
+const rollbackTimeout = 2 * time.Minute
+
+// snapshot exports the teleporter archive of every replica before it is modified.
+// In best effort mode replicas that cannot be snapshotted are failed so they are never modified without a restore point.
+func (target *target) snapshot(ctx context.Context) error {
+	log.Info().Msg("Snapshotting replicas...")
+
+	return target.forEachReplica(func(replica pihole.Client) error {
+		var payload []byte
+		if err := retry.Fixed(ctx, func() error {
+			var err error
+			payload, err = replica.GetTeleporter(ctx)
+			return err
+		}, retry.AttemptsGetTeleporter); err != nil {
+			return fmt.Errorf("snapshot: %w", err)
+		}
+
+		target.run.setSnapshot(replica, payload)
+		return nil
+	})
+}
+
+// modify marks replica as changed by this run, making it a candidate for rollback.
+func (target *target) modify(replica pihole.Client) {
+	if target.run != nil {
+		target.run.modify(replica)
+	}
+}
+
+// rollback restores the snapshot of every modified replica that has to be reverted.
+// When the run was aborted all modified replicas are restored, otherwise only the ones that failed.
+func (target *target) rollback(ctx context.Context, aborted bool) error {
+	var replicas []pihole.Client
+	for _, replica := range target.Replicas {
+		if target.run.restorable(replica) && (aborted || target.run.failed(replica)) {
+			replicas = append(replicas, replica)
+		}
+	}
+
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	log.Warn().Int("replicas", len(replicas)).Msg("Rolling back replicas...")
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+
+	errs := target.runParallel(replicas, func(replica pihole.Client) error {
+		if err := retry.Fixed(ctx, func() error {
+			return replica.PostTeleporter(ctx, target.run.snapshot(replica), createRestoreTeleporterRequest())
+		}, retry.AttemptsPostTeleporter); err != nil {
+			return fmt.Errorf("rollback %s: %w", replica.String(), err)
+		}
+
+		target.forget(replica)
+		log.Warn().Str("replica", replica.String()).Msg("Replica rolled back")
+		return nil
+	}, false)
+
+	for i, replica := range replicas {
+		if errs[i] != nil {
+			if failure, failed := target.run.failures[replica]; failed {
+				target.run.failures[replica] = errors.Join(failure, errs[i])
+			}
+			continue
+		}
+		target.run.rolledBack[replica] = true
+	}
+
+	return errors.Join(errs...)
+}
+
+// rollbackError annotates the error of an aborted run with the outcome of the rollback.
+func (target *target) rollbackError(err, rollbackErr error) error {
+	var rolledBack []string
+	for _, replica := range target.Replicas {
+		if target.run.rolledBack[replica] {
+			rolledBack = append(rolledBack, replica.String())
+		}
+	}
+
+	if len(rolledBack) > 0 {
+		err = fmt.Errorf("%w (rolled back: %s)", err, strings.Join(rolledBack, ", "))
+	}
+	if rollbackErr != nil {
+		err = errors.Join(err, rollbackErr)
+	}
+	return err
+}
+
+// forget drops the stored checksums of replica, its state no longer matches what was applied.
+func (target *target) forget(replica pihole.Client) {
+	if target.checksums == nil {
+		return
+	}
+
+	if err := target.checksums.Forget(replica.String()); err != nil {
+		log.Warn().Err(err).Str("replica", replica.String()).Msg("Failed to clear checksum")
+	}
+}
+
+func createRestoreTeleporterRequest() *model.PostTeleporterRequest {
+	request := createPostTeleporterRequest(newFullSyncGravitySettings())
+	request.Config = true
+	return request
+}
+
+func (r *run) setSnapshot(replica pihole.Client, payload []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snapshots[replica] = payload
+}
+
+func (r *run) snapshot(replica pihole.Client) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshots[replica]
+}
+
+func (r *run) modify(replica pihole.Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modified[replica] = true
+}
+
+// restorable reports whether replica was modified during this run and has a snapshot to restore.
+func (r *run) restorable(replica pihole.Client) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, snapshotted := r.snapshots[replica]
+	return snapshotted && r.modified[replica]
+}
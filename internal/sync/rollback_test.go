@@ -0,0 +1,193 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/sync/retry"
+)
+
+func TestTarget_SelectiveSync_rollback(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	target := NewTarget(primary, []pihole.Client{replica}, 1, nil)
+
+	snapshot := []byte("snapshot")
+	patchErr := errors.New("patch error")
+
+	configResponse := emptyConfigResponse()
+	configResponse.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}}
+
+	configSettings := disabledConfigSettings()
+	configSettings.DNS = config.NewConfigSetting(true, nil, nil)
+
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	replica.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+
+	replica.EXPECT().GetTeleporter(mock.Anything).Once().Return(snapshot, nil)
+
+	primary.EXPECT().GetTeleporter(mock.Anything).Once().Return([]byte{}, nil)
+	replica.EXPECT().PostTeleporter(mock.Anything, []byte{}, mock.Anything).Once().Return(nil)
+
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(configResponse, nil)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	replica.EXPECT().PatchConfig(mock.Anything, mock.Anything).Times(retry.AttemptsPatchConfig).Return(patchErr)
+
+	replica.EXPECT().PostTeleporter(mock.Anything, snapshot, createRestoreTeleporterRequest()).Once().Return(nil)
+
+	primary.EXPECT().HasSession().Return(true)
+
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	replica.EXPECT().HasSession().Return(true)
+	replica.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	err := target.SelectiveSync(context.Background(), &config.Sync{
+		Rollback:        true,
+		GravitySettings: &config.GravitySettings{},
+		ConfigSettings:  configSettings,
+	})
+
+	require.ErrorIs(t, err, patchErr)
+	assert.Contains(t, err.Error(), "rolled back: http://replica")
+}
+
+func TestTarget_SelectiveSync_rollbackCancelled(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	target := NewTarget(primary, []pihole.Client{replica}, 1, nil)
+
+	snapshot := []byte("snapshot")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	configResponse := emptyConfigResponse()
+	configResponse.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}}
+
+	configSettings := disabledConfigSettings()
+	configSettings.DNS = config.NewConfigSetting(true, nil, nil)
+
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	replica.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+
+	replica.EXPECT().GetTeleporter(mock.Anything).Once().Return(snapshot, nil)
+
+	primary.EXPECT().GetTeleporter(mock.Anything).Once().Return([]byte{}, nil)
+	replica.EXPECT().PostTeleporter(mock.Anything, []byte{}, mock.Anything).Once().Return(nil)
+
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(configResponse, nil)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	replica.EXPECT().PatchConfig(mock.Anything, mock.Anything).Once().Run(func(mock.Arguments) { cancel() }).Return(context.Canceled)
+
+	replica.EXPECT().PostTeleporter(mock.Anything, snapshot, createRestoreTeleporterRequest()).Once().Return(nil)
+
+	primary.EXPECT().HasSession().Return(true)
+
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	replica.EXPECT().HasSession().Return(true)
+	replica.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	err := target.SelectiveSync(ctx, &config.Sync{
+		Rollback:        true,
+		GravitySettings: &config.GravitySettings{},
+		ConfigSettings:  configSettings,
+	})
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Contains(t, err.Error(), "rolled back: http://replica")
+}
+
+func TestTarget_SelectiveSync_rollbackBestEffort(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	failing := piholemock.NewClient(t)
+	healthy := piholemock.NewClient(t)
+
+	target := NewTarget(primary, []pihole.Client{failing, healthy}, 1, nil)
+
+	snapshot := []byte("snapshot")
+	patchErr := errors.New("patch error")
+
+	configResponse := emptyConfigResponse()
+	configResponse.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}}
+
+	configSettings := disabledConfigSettings()
+	configSettings.DNS = config.NewConfigSetting(true, nil, nil)
+
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	failing.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	healthy.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	failing.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	healthy.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+
+	failing.EXPECT().GetTeleporter(mock.Anything).Once().Return(snapshot, nil)
+	healthy.EXPECT().GetTeleporter(mock.Anything).Once().Return(snapshot, nil)
+
+	primary.EXPECT().GetTeleporter(mock.Anything).Once().Return([]byte{}, nil)
+	failing.EXPECT().PostTeleporter(mock.Anything, []byte{}, mock.Anything).Once().Return(nil)
+	healthy.EXPECT().PostTeleporter(mock.Anything, []byte{}, mock.Anything).Once().Return(nil)
+
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(configResponse, nil)
+	failing.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	healthy.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	failing.EXPECT().PatchConfig(mock.Anything, mock.Anything).Times(retry.AttemptsPatchConfig).Return(patchErr)
+	healthy.EXPECT().PatchConfig(mock.Anything, mock.Anything).Once().Return(nil)
+
+	failing.EXPECT().PostTeleporter(mock.Anything, snapshot, createRestoreTeleporterRequest()).Once().Return(nil)
+
+	primary.EXPECT().HasSession().Return(true)
+
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	failing.EXPECT().HasSession().Return(true)
+	failing.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	healthy.EXPECT().HasSession().Return(true)
+	healthy.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+
+	failing.EXPECT().String().Return("http://failing")
+	healthy.EXPECT().String().Return("http://healthy")
+
+	err := target.SelectiveSync(context.Background(), &config.Sync{
+		BestEffort:      true,
+		Rollback:        true,
+		GravitySettings: &config.GravitySettings{},
+		ConfigSettings:  configSettings,
+	})
+
+	var replicasError *ReplicasError
+	require.ErrorAs(t, err, &replicasError)
+	assert.Equal(t, []ReplicaOutcome{
+		{Replica: "http://failing", Success: false, Error: "patch error", RolledBack: true},
+		{Replica: "http://healthy", Success: true},
+	}, replicasError.Outcomes)
+}
+
+func TestTarget_rollback_unmodified(t *testing.T) {
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{Rollback: true}),
+	}
+	target.run.setSnapshot(replica, []byte("snapshot"))
+
+	require.NoError(t, target.rollback(context.Background(), true))
+	replica.AssertNotCalled(t, "PostTeleporter", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,84 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+)
+
+// readyPollInterval is the time between two readiness checks of a restarting replica. The first check also waits
+// this long, since FTL restarts only after it answered the request that triggered the restart.
+var readyPollInterval = 2 * time.Second
+
+// rolling holds the settings of a rolling update, where replicas restart one at a time.
+type rolling struct {
+	delay    time.Duration
+	timeout  time.Duration
+	dnsQuery string
+}
+
+// awaitReady blocks after an action restarted FTL on replica until its API and DNS serve again, then waits for
+// the configured delay before the next replica is updated. It returns immediately without a rolling update.
+func (target *target) awaitReady(ctx context.Context, replica pihole.Client) error {
+	if target.run == nil || target.run.rolling == nil {
+		return nil
+	}
+	rolling := target.run.rolling
+
+	log.Info().Str("replica", replica.String()).Msg("Waiting for replica to serve again...")
+
+	deadline := time.Now().Add(rolling.timeout)
+	for {
+		if err := sleep(ctx, readyPollInterval); err != nil {
+			return err
+		}
+
+		err := ready(ctx, replica, rolling.dnsQuery)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("replica %s not ready after %s: %w", replica.String(), rolling.timeout, err)
+		}
+		log.Debug().Str("replica", replica.String()).Err(err).Msg("Replica not ready yet")
+	}
+
+	log.Info().Str("replica", replica.String()).Msg("Replica is serving again")
+
+	if rolling.delay > 0 {
+		log.Info().Str("delay", rolling.delay.String()).Msg("Delaying next replica")
+		return sleep(ctx, rolling.delay)
+	}
+
+	return nil
+}
+
+// ready checks that the API of replica answers and, if query is set, that it resolves query.
+func ready(ctx context.Context, replica pihole.Client, query string) error {
+	if err := replica.Ready(ctx); err != nil {
+		return err
+	}
+
+	if query == "" {
+		return nil
+	}
+
+	return resolve(ctx, replica, query)
+}
+
+// sleep waits for d, or returns the error of ctx if it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,104 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+)
+
+func setReadyPollInterval(t *testing.T, interval time.Duration) {
+	t.Helper()
+
+	previous := readyPollInterval
+	readyPollInterval = interval
+	t.Cleanup(func() { readyPollInterval = previous })
+}
+
+func Test_target_awaitReady(t *testing.T) {
+	setReadyPollInterval(t, time.Millisecond)
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{Rolling: true, RollingTimeout: time.Second}),
+	}
+
+	replica.EXPECT().Ready(mock.Anything).Once().Return(errors.New("connection refused"))
+	replica.EXPECT().Ready(mock.Anything).Once().Return(nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	require.NoError(t, target.awaitReady(context.Background(), replica))
+}
+
+func Test_target_awaitReady_timeout(t *testing.T) {
+	setReadyPollInterval(t, time.Millisecond)
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{Rolling: true, RollingTimeout: 10 * time.Millisecond}),
+	}
+
+	replica.EXPECT().Ready(mock.Anything).Return(errors.New("connection refused"))
+	replica.EXPECT().String().Return("http://replica")
+
+	assert.ErrorContains(
+		t,
+		target.awaitReady(context.Background(), replica),
+		"replica http://replica not ready after 10ms: connection refused",
+	)
+}
+
+func Test_target_awaitReady_canceled(t *testing.T) {
+	setReadyPollInterval(t, time.Minute)
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{Rolling: true, RollingTimeout: time.Hour}),
+	}
+
+	replica.EXPECT().String().Return("http://replica")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, target.awaitReady(ctx, replica), context.Canceled)
+	replica.AssertNotCalled(t, "Ready", mock.Anything)
+}
+
+func Test_target_syncTeleporters_rolling(t *testing.T) {
+	setReadyPollInterval(t, time.Millisecond)
+	primary := piholemock.NewClient(t)
+	first := piholemock.NewClient(t)
+	second := piholemock.NewClient(t)
+
+	target := target{
+		Primary:     primary,
+		Replicas:    []pihole.Client{first, second},
+		Parallelism: 2,
+		run:         newRun(&config.Sync{Rolling: true, RollingTimeout: time.Second}),
+	}
+	assert.Equal(t, 1, target.workers())
+
+	payload := []byte("teleporter")
+	primary.EXPECT().GetTeleporter(mock.Anything).Once().Return(payload, nil)
+
+	firstImport := first.EXPECT().PostTeleporter(mock.Anything, payload, createPostTeleporterRequest(&config.GravitySettings{})).Once().Return(nil)
+	firstReady := first.EXPECT().Ready(mock.Anything).Once().Return(nil).NotBefore(firstImport)
+	second.EXPECT().PostTeleporter(mock.Anything, payload, createPostTeleporterRequest(&config.GravitySettings{})).Once().Return(nil).NotBefore(firstReady)
+	second.EXPECT().Ready(mock.Anything).Once().Return(nil)
+	first.EXPECT().String().Return("http://first")
+	second.EXPECT().String().Return("http://second")
+
+	require.NoError(t, target.syncTeleporters(context.Background(), &config.GravitySettings{}))
+}
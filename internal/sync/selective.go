@@ -1,28 +1,43 @@
 package sync
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/lovelaze/nebula-sync/internal/config"
 )
 
-func (target *target) SelectiveSync(conf *config.Sync) error {
-	return target.sync(func() error {
-		return target.selective(conf)
+func (target *target) SelectiveSync(ctx context.Context, conf *config.Sync) error {
+	return target.sync(ctx, conf, func() error {
+		return target.rollout(ctx, conf, func() error {
+			return target.selective(ctx, conf)
+		})
 	}, "selective")
 }
 
-func (target *target) selective(conf *config.Sync) error {
-	if err := target.syncTeleporters(conf.GravitySettings); err != nil {
-		return fmt.Errorf("sync teleporters: %w", err)
+func (target *target) selective(ctx context.Context, conf *config.Sync) error {
+	if conf.Rollback {
+		if err := target.snapshot(ctx); err != nil {
+			return fmt.Errorf("snapshot replicas: %w", err)
+		}
+	}
+
+	if err := target.syncGravity(ctx, conf.GravitySettings); err != nil {
+		return fmt.Errorf("sync gravity: %w", err)
 	}
 
-	if err := target.syncConfigs(conf.ConfigSettings); err != nil {
+	if err := target.syncConfigs(ctx, conf.ConfigSettings); err != nil {
 		return fmt.Errorf("sync configs: %w", err)
 	}
 
+	if conf.Verify {
+		if err := target.verifyConfigs(ctx); err != nil {
+			return fmt.Errorf("verify configs: %w", err)
+		}
+	}
+
 	if conf.RunGravity {
-		if err := target.runGravity(); err != nil {
+		if err := target.runGravity(ctx, conf.GravitySettings); err != nil {
 			return fmt.Errorf("run gravity: %w", err)
 		}
 	}
@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -15,7 +16,7 @@ func TestTarget_SelectiveSync(t *testing.T) {
 	primary := piholemock.NewClient(t)
 	replica := piholemock.NewClient(t)
 
-	target := NewTarget(primary, []pihole.Client{replica})
+	target := NewTarget(primary, []pihole.Client{replica}, 1, nil)
 
 	settings := config.Sync{
 		FullSync:   false,
@@ -43,21 +44,32 @@ func TestTarget_SelectiveSync(t *testing.T) {
 		},
 	}
 
-	primary.EXPECT().PostAuth().Once().Return(nil)
-	replica.EXPECT().PostAuth().Once().Return(nil)
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	replica.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
 
-	primary.EXPECT().GetTeleporter().Once().Return([]byte{}, nil)
-	replica.EXPECT().PostTeleporter(mock.Anything, mock.Anything).Once().Return(nil)
+	primary.EXPECT().GetTeleporter(mock.Anything).Once().Return([]byte{}, nil)
+	replica.EXPECT().PostTeleporter(mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
 
-	primary.EXPECT().GetConfig().Once().Return(emptyConfigResponse(), nil)
-	replica.EXPECT().PatchConfig(mock.Anything).Once().Return(nil)
+	primaryConfig := emptyConfigResponse()
+	primaryConfig.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}}
 
-	primary.EXPECT().PostRunGravity().Once().Return(nil)
-	replica.EXPECT().PostRunGravity().Once().Return(nil)
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(primaryConfig, nil)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	replica.EXPECT().PatchConfig(mock.Anything, mock.Anything).Once().Return(nil)
+	replica.EXPECT().String().Return("http://replica").Maybe()
 
-	primary.EXPECT().DeleteSession().Once().Return(nil)
-	replica.EXPECT().DeleteSession().Once().Return(nil)
+	primary.EXPECT().PostRunGravity(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostRunGravity(mock.Anything).Once().Return(nil)
 
-	err := target.SelectiveSync(&settings)
+	primary.EXPECT().HasSession().Return(true)
+
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	replica.EXPECT().HasSession().Return(true)
+	replica.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+
+	err := target.SelectiveSync(context.Background(), &settings)
 	require.NoError(t, err)
 }
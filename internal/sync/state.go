@@ -1,11 +1,18 @@
 package sync
 
-import "time"
+import (
+	"errors"
+	gosync "sync"
+	"time"
+)
 
 const stackSize = 5
 
 type State struct {
-	Stack []Outcome
+	mu       gosync.RWMutex
+	Stack    []Outcome
+	drift    *Drift
+	versions *Versions
 }
 
 func NewState() *State {
@@ -15,8 +22,11 @@ func NewState() *State {
 }
 
 type Outcome struct {
-	Timestamp time.Time
-	Success   bool
+	Timestamp time.Time        `json:"timestamp"`
+	Success   bool             `json:"success"`
+	Partial   bool             `json:"partial"`
+	Error     string           `json:"error,omitempty"`
+	Replicas  []ReplicaOutcome `json:"replicas,omitempty"`
 }
 
 func NewOutcome(success bool) *Outcome {
@@ -26,7 +36,23 @@ func NewOutcome(success bool) *Outcome {
 	}
 }
 
+func newFailureOutcome(err error) *Outcome {
+	outcome := NewOutcome(false)
+	outcome.Error = err.Error()
+
+	var replicasError *ReplicasError
+	if errors.As(err, &replicasError) {
+		outcome.Partial = replicasError.Partial()
+		outcome.Replicas = replicasError.Outcomes
+	}
+
+	return outcome
+}
+
 func (s *State) Add(outcome Outcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.Stack = append([]Outcome{outcome}, s.Stack...)
 
 	if len(s.Stack) > stackSize {
@@ -34,10 +60,48 @@ func (s *State) Add(outcome Outcome) {
 	}
 }
 
+// Outcomes returns a copy of the stack, latest outcome first.
+func (s *State) Outcomes() []Outcome {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]Outcome{}, s.Stack...)
+}
+
 func (s *State) OnSuccess() {
 	s.Add(*NewOutcome(true))
 }
 
 func (s *State) OnFailure(err error) {
-	s.Add(*NewOutcome(false))
+	s.Add(*newFailureOutcome(err))
+}
+
+func (s *State) OnDrift(drift *Drift) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.drift = drift
+}
+
+// Drift returns the drift found by the latest detect run, nil if none completed yet.
+func (s *State) Drift() *Drift {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.drift
+}
+
+func (s *State) OnVersions(versions *Versions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.versions = versions
+}
+
+// Versions returns the versions detected by the latest run, nil if none detected them yet.
+func (s *State) Versions() *Versions {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.versions
 }
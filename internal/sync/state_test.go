@@ -43,3 +43,17 @@ func TestState_OnFailure(t *testing.T) {
 	now := time.Now()
 	assert.WithinRange(t, s.Stack[0].Timestamp, now.Add(-1*time.Second), now.Add(1*time.Second))
 }
+
+func TestState_OnFailure_partial(t *testing.T) {
+	s := NewState()
+
+	s.OnFailure(&ReplicasError{Outcomes: []ReplicaOutcome{
+		{Replica: "http://replica1", Success: true},
+		{Replica: "http://replica2", Success: false, Error: "test error"},
+	}})
+
+	require.Len(t, s.Stack, 1)
+	assert.False(t, s.Stack[0].Success)
+	assert.True(t, s.Stack[0].Partial)
+	assert.Len(t, s.Stack[0].Replicas, 2)
+}
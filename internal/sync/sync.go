@@ -1,186 +1,446 @@
 package sync
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	gosync "sync"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/lovelaze/nebula-sync/internal/config"
 	"github.com/lovelaze/nebula-sync/internal/pihole"
 	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/sync/checksum"
+	"github.com/lovelaze/nebula-sync/internal/sync/diff"
 	"github.com/lovelaze/nebula-sync/internal/sync/filter"
+	"github.com/lovelaze/nebula-sync/internal/sync/merge"
 	"github.com/lovelaze/nebula-sync/internal/sync/retry"
+	"github.com/lovelaze/nebula-sync/internal/sync/transform"
 )
 
 type Target interface {
-	FullSync(sync *config.Sync) error
-	SelectiveSync(sync *config.Sync) error
+	FullSync(ctx context.Context, sync *config.Sync) error
+	SelectiveSync(ctx context.Context, sync *config.Sync) error
+	Plan(ctx context.Context, sync *config.Sync) (*Plan, error)
+	Detect(ctx context.Context, sync *config.Sync) (*Drift, error)
+	RunGravity(ctx context.Context, sync *config.Sync) error
+	Close(ctx context.Context)
 }
 
 type target struct {
-	Primary  pihole.Client
-	Replicas []pihole.Client
-	Client   *config.Client
+	Primary     pihole.Client
+	Replicas    []pihole.Client
+	Client      *config.Client
+	Parallelism int
+	checksums   *checksum.Store
+	run         *run
+
+	// versions are the versions detected by the latest run, read concurrently by the API
+	versionsMu gosync.RWMutex
+	versions   *Versions
 }
 
-func NewTarget(primary pihole.Client, replicas []pihole.Client) Target {
+func NewTarget(primary pihole.Client, replicas []pihole.Client, parallelism int, checksums *checksum.Store) Target {
 	return &target{
-		Primary:  primary,
-		Replicas: replicas,
+		Primary:     primary,
+		Replicas:    replicas,
+		Parallelism: parallelism,
+		checksums:   checksums,
 	}
 }
 
-func (target *target) sync(syncFunc func() error, mode string) error {
-	var err error
-	log.Info().Str("mode", mode).Int("replicas", len(target.Replicas)).Msg("Running sync")
-
-	defer func() {
-		if err != nil {
-			log.Error().Err(err).Msg("Error during sync")
-		}
-		target.deleteSessions()
-	}()
+// sync runs syncFunc in an authenticated session. Unless sessions are kept for the next run, they are invalidated
+// even if ctx is done.
+func (target *target) sync(ctx context.Context, conf *config.Sync, syncFunc func() error, mode string) error {
+	target.run = newRun(conf)
+
+	log.Info().
+		Str("mode", mode).
+		Int("replicas", len(target.Replicas)).
+		Int("parallelism", target.workers()).
+		Bool("best_effort", conf.BestEffort).
+		Bool("force", conf.ForceSync).
+		Bool("rollback", conf.Rollback).
+		Bool("rolling", conf.Rolling).
+		Msg("Running sync")
+
+	if !conf.KeepSessions {
+		defer target.deleteSessions(context.WithoutCancel(ctx))
+	}
 
-	if err := target.authenticate(); err != nil {
+	if err := target.authenticate(ctx); err != nil {
 		return fmt.Errorf("authenticate: %w", err)
 	}
 
-	return syncFunc()
+	if err := target.detectVersions(ctx); err != nil {
+		return fmt.Errorf("detect versions: %w", err)
+	}
+
+	if err := syncFunc(); err != nil {
+		return target.rollbackError(err, target.rollback(ctx, true))
+	}
+
+	if err := target.rollback(ctx, false); err != nil {
+		log.Warn().Err(err).Msg("Rollback failed")
+	}
+
+	return target.run.err(target.Replicas)
 }
 
-func (target *target) authenticate() error {
+func (target *target) authenticate(ctx context.Context) error {
 	log.Info().Msg("Authenticating clients...")
-	if err := target.Primary.PostAuth(); err != nil {
+	if err := target.login(ctx, target.Primary); err != nil {
 		return err
 	}
 
-	for _, replica := range target.Replicas {
-		if err := retry.Fixed(func() error {
-			return replica.PostAuth()
-		}, retry.AttemptsPostAuth); err != nil {
-			return err
-		}
+	return target.forEachReplica(func(replica pihole.Client) error {
+		return retry.Fixed(ctx, func() error {
+			return target.login(ctx, replica)
+		}, retry.AttemptsPostAuth)
+	})
+}
+
+// login authenticates client, unless it still holds a session of a previous run that can be reused.
+func (target *target) login(ctx context.Context, client pihole.Client) error {
+	if target.run != nil && target.run.keepSessions && client.HasSession() {
+		log.Debug().Str("target", client.String()).Msg("Reusing session")
+		return nil
 	}
 
-	return nil
+	return client.PostAuth(ctx)
 }
 
-func (target *target) deleteSessions() {
+// Close invalidates the sessions kept between runs.
+func (target *target) Close(ctx context.Context) {
+	target.deleteSessions(ctx)
+}
+
+func (target *target) deleteSessions(ctx context.Context) {
 	log.Info().Msg("Invalidating sessions...")
-	if err := target.Primary.DeleteSession(); err != nil {
-		log.Warn().Msgf("Failed to invalidate session for target: %s", target.Primary.String())
+	if target.Primary.HasSession() {
+		if err := target.Primary.DeleteSession(ctx); err != nil {
+			log.Warn().Msgf("Failed to invalidate session for target: %s", target.Primary.String())
+		}
 	}
 
-	for _, replica := range target.Replicas {
-		if err := retry.Fixed(func() error {
-			return replica.DeleteSession()
+	target.runParallel(target.Replicas, func(replica pihole.Client) error {
+		if !replica.HasSession() {
+			return nil
+		}
+		if err := retry.Fixed(ctx, func() error {
+			return replica.DeleteSession(ctx)
 		}, retry.AttemptsDeleteSession); err != nil {
 			log.Warn().Msgf("Failed to invalidate session for target: %s", replica.String())
 		}
+		return nil
+	}, false)
+}
+
+// syncGravity syncs the gravity database with the engine of the run, teleporter imports unless records are synced.
+func (target *target) syncGravity(ctx context.Context, gravitySettings *config.GravitySettings) error {
+	if target.run != nil && target.run.records {
+		return target.syncRecords(ctx, gravitySettings)
 	}
+	return target.syncTeleporters(ctx, gravitySettings)
 }
 
-func (target *target) syncTeleporters(gravitySettings *config.GravitySettings) error {
+func (target *target) syncTeleporters(ctx context.Context, gravitySettings *config.GravitySettings) error {
 	log.Info().Msg("Syncing teleporters...")
-	conf, err := target.Primary.GetTeleporter()
+	conf, err := target.Primary.GetTeleporter(ctx)
 	if err != nil {
 		return err
 	}
 
-	var teleporterRequest *model.PostTeleporterRequest
-	if gravitySettings != nil {
-		teleporterRequest = createPostTeleporterRequest(gravitySettings)
+	gravity, err := target.gravityChecksum(ctx)
+	if err != nil {
+		return err
 	}
 
-	for _, replica := range target.Replicas {
-		if err := retry.Fixed(func() error {
-			return replica.PostTeleporter(conf, teleporterRequest)
+	return target.forEachReplica(func(replica pihole.Client) error {
+		var teleporterRequest *model.PostTeleporterRequest
+		if settings := target.override(replica).ApplyGravity(gravitySettings); settings != nil {
+			teleporterRequest = createPostTeleporterRequest(settings)
+		}
+
+		sum, err := checksum.Teleporter(conf, gravity, teleporterRequest)
+		if err != nil {
+			return err
+		}
+
+		if target.unchanged(replica, checksum.KindTeleporter, sum) {
+			log.Info().Str("replica", replica.String()).Msg("Teleporter unchanged, skipping import")
+			return nil
+		}
+
+		target.modify(replica)
+		if err := retry.Fixed(ctx, func() error {
+			return replica.PostTeleporter(ctx, conf, teleporterRequest)
 		}, retry.AttemptsPostTeleporter); err != nil {
 			return err
 		}
+		if err := target.awaitReady(ctx, replica); err != nil {
+			return err
+		}
+
+		target.remember(replica, checksum.KindTeleporter, sum)
+		return nil
+	})
+}
+
+// gravityChecksum returns the checksum of the primary's gravity entries and remembers the checksum of its adlists for
+// this run. It is empty without a checksum store, since nothing is skipped then.
+func (target *target) gravityChecksum(ctx context.Context) (string, error) {
+	if target.checksums == nil {
+		return "", nil
+	}
+
+	records, err := getGravityRecords(ctx, target.Primary, newFullSyncGravitySettings())
+	if err != nil {
+		return "", err
+	}
+	if target.run != nil {
+		target.run.adlists = adlistsChecksum(records.lists)
 	}
 
-	return err
+	return records.checksum()
 }
 
-func (target *target) syncConfigs(configSettings *config.ConfigSettings) error {
+func (target *target) syncConfigs(ctx context.Context, configSettings *config.ConfigSettings) error {
 	log.Info().Msg("Syncing configs...")
-	configResponse, err := target.Primary.GetConfig()
+	configResponse, err := target.Primary.GetConfig(ctx)
 	if err != nil {
 		return err
 	}
 
-	configRequest := createPatchConfigRequest(configSettings, configResponse)
+	return target.forEachReplica(func(replica pihole.Client) error {
+		configRequest := createPatchConfigRequest(target.override(replica).ApplyConfig(configSettings), configResponse)
+		desired, err := target.transform(replica, configRequest.Config.Map())
+		if err != nil {
+			return err
+		}
+		if target.run != nil {
+			target.run.setDesired(replica, desired)
+		}
+
+		sum, err := checksum.Of(desired)
+		if err != nil {
+			return err
+		}
+
+		if target.unchanged(replica, checksum.KindConfig, sum) {
+			log.Info().Str("replica", replica.String()).Msg("Config unchanged, skipping patch")
+			return nil
+		}
 
-	for _, replica := range target.Replicas {
-		if err := retry.Fixed(func() error {
-			return replica.PatchConfig(configRequest)
-		}, retry.AttemptsPatchConfig); err != nil {
+		changes, err := target.configChanges(ctx, replica, desired)
+		if err != nil {
 			return err
 		}
+
+		if len(changes) == 0 {
+			log.Info().Str("replica", replica.String()).Msg("Config already in sync, skipping patch")
+		} else {
+			log.Info().Str("replica", replica.String()).Strs("keys", diff.Keys(changes)).Msg("Patching config")
+
+			patchRequest := &model.PatchConfigRequest{Config: model.NewPatchConfig(diff.Patch(changes))}
+			target.modify(replica)
+			if err := retry.Fixed(ctx, func() error {
+				return replica.PatchConfig(ctx, patchRequest)
+			}, retry.AttemptsPatchConfig); err != nil {
+				return err
+			}
+		}
+
+		target.remember(replica, checksum.KindConfig, sum)
+		return nil
+	})
+}
+
+// configChanges returns the leaves of desired that differ from the replica's current config, after merging the
+// arrays that have element rules with those of the replica.
+func (target *target) configChanges(ctx context.Context, replica pihole.Client, desired map[string]any) ([]diff.Change, error) {
+	var replicaConfig *model.ConfigResponse
+	if err := retry.Fixed(ctx, func() error {
+		var err error
+		replicaConfig, err = replica.GetConfig(ctx)
+		return err
+	}, retry.AttemptsGetConfig); err != nil {
+		return nil, fmt.Errorf("get replica config: %w", err)
+	}
+
+	if target.run != nil {
+		desired = merge.Apply(target.run.merges, desired, replicaConfig.Config)
+	}
+
+	changes := diff.Changes(replicaConfig.Config, desired)
+	if target.run == nil || target.run.compatibility(replica) != CompatibilityLimited {
+		return changes, nil
 	}
 
-	return err
+	// an older replica rejects the whole patch if it contains a key it does not have
+	changes, unsupported := diff.Existing(replicaConfig.Config, changes)
+	for _, key := range unsupported {
+		log.Warn().Str("replica", replica.String()).Str("key", key).Msg("Config key not supported by replica, skipping")
+	}
+	return changes, nil
 }
 
-func (target *target) runGravity() error {
+// runGravity runs gravity on the primary and every replica whose adlists changed since gravity last ran on it.
+func (target *target) runGravity(ctx context.Context, gravitySettings *config.GravitySettings) error {
 	log.Info().Msg("Running gravity...")
 
-	if err := target.Primary.PostRunGravity(); err != nil {
-		return err
+	adlists := ""
+	if target.run != nil {
+		adlists = target.run.adlists
+	}
+
+	// a canary rollout runs the stages twice, gravity only has to run once on the primary
+	if target.run == nil || !target.run.primaryGravity {
+		if target.gravityNeeded(target.Primary, adlists) {
+			if err := target.Primary.PostRunGravity(ctx); err != nil {
+				return err
+			}
+			target.remember(target.Primary, checksum.KindGravity, adlists)
+		}
+		if target.run != nil {
+			target.run.primaryGravity = true
+		}
 	}
 
-	for _, replica := range target.Replicas {
-		if err := retry.Fixed(func() error {
-			return replica.PostRunGravity()
+	return target.forEachReplica(func(replica pihole.Client) error {
+		// adlists are only changed by a sync if they are imported
+		if settings := target.override(replica).ApplyGravity(gravitySettings); settings != nil && !settings.Adlist {
+			log.Info().Str("replica", replica.String()).Msg("Adlists not synced, skipping gravity")
+			return nil
+		}
+
+		if !target.gravityNeeded(replica, adlists) {
+			return nil
+		}
+
+		if err := retry.Fixed(ctx, func() error {
+			return replica.PostRunGravity(ctx)
 		}, retry.AttemptsPostRunGravity); err != nil {
 			return err
 		}
+		if err := target.awaitReady(ctx, replica); err != nil {
+			return err
+		}
+
+		target.remember(replica, checksum.KindGravity, adlists)
+		return nil
+	})
+}
+
+// gravityNeeded reports whether gravity has to run on client for the adlists checksum. Gravity always runs if the
+// adlists of the primary are unknown.
+func (target *target) gravityNeeded(client pihole.Client, adlists string) bool {
+	if adlists == "" || !target.unchanged(client, checksum.KindGravity, adlists) {
+		return true
 	}
 
-	return nil
+	log.Info().Str("target", client.String()).Msg("Adlists unchanged, skipping gravity")
+	return false
+}
+
+// override returns the settings override of replica, nil if it has none.
+func (target *target) override(replica pihole.Client) *config.ReplicaOverride {
+	if target.run == nil {
+		return nil
+	}
+
+	i := slices.Index(target.Replicas, replica)
+	if i < 0 || i >= len(target.run.overrides) {
+		return nil
+	}
+	return target.run.overrides[i]
+}
+
+// transform applies the transformation rules to the config desired for replica.
+func (target *target) transform(replica pihole.Client, desired map[string]any) (map[string]any, error) {
+	if target.run == nil || len(target.run.transforms) == 0 {
+		return desired, nil
+	}
+
+	vars := transform.NewVars(replica.String(), slices.Index(target.Replicas, replica)+1)
+	return transform.Apply(target.run.transforms, desired, vars)
+}
+
+// unchanged reports whether sum was already applied to the replica by a previous run.
+func (target *target) unchanged(replica pihole.Client, kind, sum string) bool {
+	if target.checksums == nil || (target.run != nil && target.run.force) {
+		return false
+	}
+
+	return target.checksums.Matches(replica.String(), kind, sum)
+}
+
+func (target *target) remember(replica pihole.Client, kind, sum string) {
+	if target.checksums == nil || sum == "" {
+		return
+	}
+
+	if err := target.checksums.Set(replica.String(), kind, sum); err != nil {
+		log.Warn().Err(err).Str("replica", replica.String()).Msg("Failed to store checksum")
+	}
 }
 
 func createPatchConfigRequest(config *config.ConfigSettings, configResponse *model.ConfigResponse) *model.PatchConfigRequest {
 	patchConfig := model.PatchConfig{}
 
-	if json := filterPatchConfigRequest(config.DNS, configResponse.Get("dns")); json != nil {
+	if json := filterPatchConfigRequest("dns", config.DNS, configResponse.Get("dns")); json != nil {
 		patchConfig.DNS = json
 	}
-	if json := filterPatchConfigRequest(config.DHCP, configResponse.Get("dhcp")); json != nil {
+	if json := filterPatchConfigRequest("dhcp", config.DHCP, configResponse.Get("dhcp")); json != nil {
 		patchConfig.DHCP = json
 	}
-	if json := filterPatchConfigRequest(config.NTP, configResponse.Get("ntp")); json != nil {
+	if json := filterPatchConfigRequest("ntp", config.NTP, configResponse.Get("ntp")); json != nil {
 		patchConfig.NTP = json
 	}
-	if json := filterPatchConfigRequest(config.Resolver, configResponse.Get("resolver")); json != nil {
+	if json := filterPatchConfigRequest("resolver", config.Resolver, configResponse.Get("resolver")); json != nil {
 		patchConfig.Resolver = json
 	}
-	if json := filterPatchConfigRequest(config.Database, configResponse.Get("database")); json != nil {
+	if json := filterPatchConfigRequest("database", config.Database, configResponse.Get("database")); json != nil {
 		patchConfig.Database = json
 	}
-	if json := filterPatchConfigRequest(config.Misc, configResponse.Get("misc")); json != nil {
+	if json := filterPatchConfigRequest("webserver", config.Webserver, configResponse.Get("webserver")); json != nil {
+		patchConfig.Webserver = withoutProtectedKeys("webserver", json)
+	}
+	if json := filterPatchConfigRequest("files", config.Files, configResponse.Get("files")); json != nil {
+		patchConfig.Files = withoutProtectedKeys("files", json)
+	}
+	if json := filterPatchConfigRequest("misc", config.Misc, configResponse.Get("misc")); json != nil {
 		patchConfig.Misc = json
 	}
-	if json := filterPatchConfigRequest(config.Debug, configResponse.Get("debug")); json != nil {
+	if json := filterPatchConfigRequest("debug", config.Debug, configResponse.Get("debug")); json != nil {
 		patchConfig.Debug = json
 	}
 
 	return &model.PatchConfigRequest{Config: patchConfig}
 }
 
-func filterPatchConfigRequest(setting *config.ConfigSetting, json map[string]any) map[string]any {
+func filterPatchConfigRequest(section string, setting *config.ConfigSetting, json map[string]any) map[string]any {
 	if !setting.Enabled {
 		return nil
 	}
 
 	if setting.Filter != nil {
-		filteredJSON, err := filter.ByType(setting.Filter.Type, setting.Filter.Keys, json)
+		filteredJSON, err := filter.ByType(setting.Filter.Type, section, setting.Filter.Keys, json)
+		if err != nil {
+			log.Warn().Err(err).Msg("Unable to filter json object")
+			return nil
+		}
+		json = filteredJSON
+	}
+
+	if len(setting.Exclude) > 0 {
+		filteredJSON, err := filter.ByType(filter.Exclude, section, setting.Exclude, json)
 		if err != nil {
 			log.Warn().Err(err).Msg("Unable to filter json object")
 			return nil
 		}
-		return filteredJSON
+		json = filteredJSON
 	}
 
 	return json
@@ -1,14 +1,22 @@
 package sync
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/lovelaze/nebula-sync/internal/config"
 	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
 	"github.com/lovelaze/nebula-sync/internal/pihole"
 	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/sync/checksum"
+	"github.com/lovelaze/nebula-sync/internal/sync/merge"
+	"github.com/lovelaze/nebula-sync/internal/sync/transform"
 )
 
 func Test_target_authenticate(t *testing.T) {
@@ -26,10 +34,10 @@ func Test_target_authenticate(t *testing.T) {
 		Client:   mockClient,
 	}
 
-	primary.EXPECT().PostAuth().Once().Return(nil)
-	replica.EXPECT().PostAuth().Once().Return(nil)
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
 
-	err := target.authenticate()
+	err := target.authenticate(context.Background())
 	assert.NoError(t, err)
 }
 
@@ -48,10 +56,74 @@ func Test_target_deleteSessions(t *testing.T) {
 		Client:   mockClient,
 	}
 
-	primary.EXPECT().DeleteSession().Once().Return(nil)
-	replica.EXPECT().DeleteSession().Once().Return(nil)
+	primary.EXPECT().HasSession().Return(true)
 
-	target.deleteSessions()
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+	replica.EXPECT().HasSession().Return(true)
+	replica.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+
+	target.deleteSessions(context.Background())
+}
+
+func Test_target_sync_canceled(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	target := target{Primary: primary}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	primary.EXPECT().PostAuth(ctx).Once().Return(context.Canceled)
+	primary.EXPECT().HasSession().Return(true)
+	primary.EXPECT().DeleteSession(mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Err() == nil
+	})).Once().Return(nil)
+
+	err := target.FullSync(ctx, &config.Sync{})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_target_authenticate_keepSessions(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	reused := piholemock.NewClient(t)
+	expired := piholemock.NewClient(t)
+
+	target := target{
+		Primary:  primary,
+		Replicas: []pihole.Client{reused, expired},
+		run:      newRun(&config.Sync{KeepSessions: true}),
+	}
+
+	primary.EXPECT().HasSession().Return(true)
+	primary.EXPECT().String().Return("http://primary")
+	reused.EXPECT().HasSession().Return(true)
+	reused.EXPECT().String().Return("http://reused")
+	expired.EXPECT().HasSession().Return(false)
+	expired.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+
+	require.NoError(t, target.authenticate(context.Background()))
+	primary.AssertNotCalled(t, "PostAuth", mock.Anything)
+	reused.AssertNotCalled(t, "PostAuth", mock.Anything)
+}
+
+func Test_target_sync_keepSessions(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	target := target{Primary: primary}
+
+	primary.EXPECT().HasSession().Return(false)
+	primary.EXPECT().PostAuth(mock.Anything).Once().Return(nil)
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+
+	_, err := target.Plan(context.Background(), &config.Sync{KeepSessions: true, ConfigSettings: &config.ConfigSettings{}})
+	require.NoError(t, err)
+	primary.AssertNotCalled(t, "DeleteSession", mock.Anything)
+
+	primary.EXPECT().HasSession().Unset()
+	primary.EXPECT().HasSession().Return(true)
+	primary.EXPECT().DeleteSession(mock.Anything).Once().Return(nil)
+
+	target.Close(context.Background())
 }
 
 func Test_target_syncTeleporters(t *testing.T) {
@@ -80,10 +152,10 @@ func Test_target_syncTeleporters(t *testing.T) {
 		ClientByGroup:     false,
 	}
 
-	primary.EXPECT().GetTeleporter().Once().Return([]byte{}, nil)
-	replica.EXPECT().PostTeleporter([]byte{}, createPostTeleporterRequest(&gravitySettings)).Once().Return(nil)
+	primary.EXPECT().GetTeleporter(mock.Anything).Once().Return([]byte{}, nil)
+	replica.EXPECT().PostTeleporter(mock.Anything, []byte{}, createPostTeleporterRequest(&gravitySettings)).Once().Return(nil)
 
-	err := target.syncTeleporters(&gravitySettings)
+	err := target.syncTeleporters(context.Background(), &gravitySettings)
 	assert.NoError(t, err)
 }
 
@@ -103,9 +175,13 @@ func Test_target_syncConfigs(t *testing.T) {
 	}
 
 	configResponse := emptyConfigResponse()
+	configResponse.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}, "interface": "eth0"}
+
+	replicaConfigResponse := emptyConfigResponse()
+	replicaConfigResponse.Config["dns"] = map[string]any{"upstreams": []any{"8.8.8.8"}, "interface": "eth0"}
 
 	gravitySettings := config.ConfigSettings{
-		DNS:       config.NewConfigSetting(false, nil, nil),
+		DNS:       config.NewConfigSetting(true, nil, nil),
 		DHCP:      config.NewConfigSetting(false, nil, nil),
 		NTP:       config.NewConfigSetting(false, nil, nil),
 		Resolver:  config.NewConfigSetting(false, nil, nil),
@@ -116,10 +192,14 @@ func Test_target_syncConfigs(t *testing.T) {
 		Debug:     config.NewConfigSetting(false, nil, nil),
 	}
 
-	primary.EXPECT().GetConfig().Once().Return(configResponse, nil)
-	replica.EXPECT().PatchConfig(createPatchConfigRequest(&gravitySettings, configResponse)).Once().Return(nil)
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(configResponse, nil)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(replicaConfigResponse, nil)
+	replica.EXPECT().PatchConfig(mock.Anything, &model.PatchConfigRequest{Config: model.PatchConfig{
+		DNS: map[string]any{"upstreams": []any{"1.1.1.1"}},
+	}}).Once().Return(nil)
+	replica.EXPECT().String().Return("http://replica")
 
-	err := target.syncConfigs(&gravitySettings)
+	err := target.syncConfigs(context.Background(), &gravitySettings)
 	assert.NoError(t, err)
 }
 
@@ -138,17 +218,70 @@ func Test_target_runGravity(t *testing.T) {
 		Client:   mockClient,
 	}
 
-	primary.EXPECT().PostRunGravity().Once().Return(nil)
-	replica.EXPECT().PostRunGravity().Once().Return(nil)
+	primary.EXPECT().PostRunGravity(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostRunGravity(mock.Anything).Once().Return(nil)
 
-	err := target.runGravity()
+	err := target.runGravity(context.Background(), nil)
 	assert.NoError(t, err)
 }
 
+func Test_target_runGravity_adlistsUnchanged(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+	skipped := piholemock.NewClient(t)
+
+	checksums, err := checksum.NewStore("")
+	require.NoError(t, err)
+
+	disabled := false
+	target := target{
+		Primary:   primary,
+		Replicas:  []pihole.Client{replica, skipped},
+		checksums: checksums,
+	}
+	conf := &config.Sync{ReplicaOverrides: []*config.ReplicaOverride{nil, {Adlist: &disabled}}}
+	gravitySettings := &config.GravitySettings{Adlist: true}
+
+	primary.EXPECT().String().Return("http://primary")
+	replica.EXPECT().String().Return("http://replica")
+	skipped.EXPECT().String().Return("http://skipped")
+	primary.EXPECT().PostRunGravity(mock.Anything).Once().Return(nil)
+	replica.EXPECT().PostRunGravity(mock.Anything).Once().Return(nil)
+
+	for range 2 {
+		target.run = newRun(conf)
+		target.run.adlists = "adlists"
+		require.NoError(t, target.runGravity(context.Background(), gravitySettings))
+	}
+}
+
+func Test_target_syncConfigs_inSync(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Primary:  primary,
+		Replicas: []pihole.Client{replica},
+	}
+
+	configResponse := emptyConfigResponse()
+	configResponse.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}}
+
+	configSettings := disabledConfigSettings()
+	configSettings.DNS = config.NewConfigSetting(true, nil, nil)
+
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(configResponse, nil)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(configResponse, nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	require.NoError(t, target.syncConfigs(context.Background(), configSettings))
+	replica.AssertNotCalled(t, "PatchConfig", mock.Anything, mock.Anything)
+}
+
 func Test_filterPatchConfigRequest_enabled(t *testing.T) {
 	dns := emptyConfigResponse().Get("dns")
 
-	request := filterPatchConfigRequest(&config.ConfigSetting{
+	request := filterPatchConfigRequest("dns", &config.ConfigSetting{
 		Enabled: true,
 		Filter:  nil,
 	}, dns)
@@ -158,7 +291,7 @@ func Test_filterPatchConfigRequest_enabled(t *testing.T) {
 func Test_filterPatchConfigRequest_disabled(t *testing.T) {
 	dns := emptyConfigResponse().Get("dns")
 
-	request := filterPatchConfigRequest(&config.ConfigSetting{
+	request := filterPatchConfigRequest("dns", &config.ConfigSetting{
 		Enabled: false,
 		Filter:  nil,
 	}, dns)
@@ -167,12 +300,262 @@ func Test_filterPatchConfigRequest_disabled(t *testing.T) {
 
 func emptyConfigResponse() *model.ConfigResponse {
 	return &model.ConfigResponse{Config: map[string]any{
-		"dns":      map[string]any{},
-		"dhcp":     map[string]any{},
-		"ntp":      map[string]any{},
-		"resolver": map[string]any{},
-		"database": map[string]any{},
-		"misc":     map[string]any{},
-		"debug":    map[string]any{},
+		"dns":       map[string]any{},
+		"dhcp":      map[string]any{},
+		"ntp":       map[string]any{},
+		"resolver":  map[string]any{},
+		"database":  map[string]any{},
+		"webserver": map[string]any{},
+		"files":     map[string]any{},
+		"misc":      map[string]any{},
+		"debug":     map[string]any{},
 	}}
 }
+
+func disabledConfigSettings() *config.ConfigSettings {
+	return &config.ConfigSettings{
+		DNS:       config.NewConfigSetting(false, nil, nil),
+		DHCP:      config.NewConfigSetting(false, nil, nil),
+		NTP:       config.NewConfigSetting(false, nil, nil),
+		Resolver:  config.NewConfigSetting(false, nil, nil),
+		Database:  config.NewConfigSetting(false, nil, nil),
+		Webserver: config.NewConfigSetting(false, nil, nil),
+		Files:     config.NewConfigSetting(false, nil, nil),
+		Misc:      config.NewConfigSetting(false, nil, nil),
+		Debug:     config.NewConfigSetting(false, nil, nil),
+	}
+}
+
+func Test_target_syncConfigs_unchanged(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	checksums, err := checksum.NewStore("")
+	require.NoError(t, err)
+
+	target := target{
+		Primary:   primary,
+		Replicas:  []pihole.Client{replica},
+		checksums: checksums,
+	}
+
+	configResponse := emptyConfigResponse()
+	configResponse.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}}
+
+	configSettings := disabledConfigSettings()
+	configSettings.DNS = config.NewConfigSetting(true, nil, nil)
+
+	primary.EXPECT().GetConfig(mock.Anything).Twice().Return(configResponse, nil)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	replica.EXPECT().PatchConfig(mock.Anything, mock.Anything).Once().Return(nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	require.NoError(t, target.syncConfigs(context.Background(), configSettings))
+	require.NoError(t, target.syncConfigs(context.Background(), configSettings))
+}
+
+func Test_target_syncTeleporters_force(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	checksums, err := checksum.NewStore("")
+	require.NoError(t, err)
+
+	target := target{
+		Primary:   primary,
+		Replicas:  []pihole.Client{replica},
+		checksums: checksums,
+		run:       newRun(&config.Sync{ForceSync: true}),
+	}
+
+	primary.EXPECT().GetTeleporter(mock.Anything).Twice().Return([]byte{}, nil)
+	expectGravityRecords(primary, primaryRecords())
+	replica.EXPECT().PostTeleporter(mock.Anything, mock.Anything, mock.Anything).Twice().Return(nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	require.NoError(t, target.syncTeleporters(context.Background(), &config.GravitySettings{}))
+	require.NoError(t, target.syncTeleporters(context.Background(), &config.GravitySettings{}))
+}
+
+func Test_target_syncTeleporters_gravityUpdated(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	checksums, err := checksum.NewStore("")
+	require.NoError(t, err)
+
+	target := target{
+		Primary:   primary,
+		Replicas:  []pihole.Client{replica},
+		checksums: checksums,
+		run:       newRun(&config.Sync{}),
+	}
+
+	// running gravity rewrites the gravity database and the list statistics, but no entry changed
+	updated := primaryRecords()
+	updated.lists[0].DateUpdated, updated.lists[0].Number = 1700000000, 42000
+
+	primary.EXPECT().GetTeleporter(mock.Anything).Once().Return(teleporterArchive(t, map[string]string{
+		"etc/pihole/gravity.db": "before gravity",
+	}), nil)
+	primary.EXPECT().GetTeleporter(mock.Anything).Once().Return(teleporterArchive(t, map[string]string{
+		"etc/pihole/gravity.db": "after gravity",
+	}), nil)
+	primary.EXPECT().GetGroups(mock.Anything).Return(primaryRecords().groups, nil)
+	primary.EXPECT().GetLists(mock.Anything).Once().Return(primaryRecords().lists, nil)
+	primary.EXPECT().GetLists(mock.Anything).Once().Return(updated.lists, nil)
+	primary.EXPECT().GetDomains(mock.Anything, "", "").Return(primaryRecords().domains, nil)
+	primary.EXPECT().GetClients(mock.Anything).Return(primaryRecords().clients, nil)
+	replica.EXPECT().PostTeleporter(mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	require.NoError(t, target.syncTeleporters(context.Background(), &config.GravitySettings{Adlist: true}))
+	adlists := target.run.adlists
+	require.NoError(t, target.syncTeleporters(context.Background(), &config.GravitySettings{Adlist: true}))
+
+	assert.NotEmpty(t, adlists)
+	assert.Equal(t, adlists, target.run.adlists)
+}
+
+func expectGravityRecords(client *piholemock.Client, records *gravityRecords) {
+	client.EXPECT().GetGroups(mock.Anything).Return(records.groups, nil)
+	client.EXPECT().GetLists(mock.Anything).Return(records.lists, nil)
+	client.EXPECT().GetDomains(mock.Anything, "", "").Return(records.domains, nil)
+	client.EXPECT().GetClients(mock.Anything).Return(records.clients, nil)
+}
+
+func Test_createPatchConfigRequest_protectedKeys(t *testing.T) {
+	configResponse := emptyConfigResponse()
+	configResponse.Config["webserver"] = map[string]any{
+		"port":      "80o,443os",
+		"interface": map[string]any{"theme": "default-dark"},
+		"api": map[string]any{
+			"pwhash":      "hash",
+			"app_pwhash":  "hash",
+			"temp":        map[string]any{"unit": "C"},
+			"max_history": 86400.0,
+		},
+		"session": map[string]any{"timeout": 1800.0},
+	}
+	configResponse.Config["files"] = map[string]any{
+		"database":  "/etc/pihole/pihole-FTL.db",
+		"macvendor": "/macvendor.db",
+	}
+
+	configSettings := disabledConfigSettings()
+	configSettings.Webserver = config.NewConfigSetting(true, []string{"port", "interface.theme", "api"}, nil)
+	configSettings.Files = config.NewConfigSetting(true, nil, nil)
+
+	request := createPatchConfigRequest(configSettings, configResponse)
+
+	assert.Equal(t, map[string]any{
+		"interface": map[string]any{"theme": "default-dark"},
+		"api": map[string]any{
+			"temp":        map[string]any{"unit": "C"},
+			"max_history": 86400.0,
+		},
+	}, request.Config.Webserver)
+	assert.Equal(t, map[string]any{"macvendor": "/macvendor.db"}, request.Config.Files)
+}
+
+func Test_target_syncConfigs_replicaOverride(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+	dhcpServer := piholemock.NewClient(t)
+
+	disabled := false
+	target := target{
+		Primary:  primary,
+		Replicas: []pihole.Client{replica, dhcpServer},
+		run: newRun(&config.Sync{ReplicaOverrides: []*config.ReplicaOverride{
+			{DHCP: &disabled, DNSExclude: []string{"interface"}},
+			nil,
+		}}),
+	}
+
+	configResponse := emptyConfigResponse()
+	configResponse.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}, "interface": "eth0"}
+	configResponse.Config["dhcp"] = map[string]any{"active": true}
+
+	configSettings := disabledConfigSettings()
+	configSettings.DNS = config.NewConfigSetting(true, nil, nil)
+	configSettings.DHCP = config.NewConfigSetting(true, nil, nil)
+
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(configResponse, nil)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	dhcpServer.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	replica.EXPECT().PatchConfig(mock.Anything, &model.PatchConfigRequest{Config: model.PatchConfig{
+		DNS: map[string]any{"upstreams": []any{"1.1.1.1"}},
+	}}).Once().Return(nil)
+	dhcpServer.EXPECT().PatchConfig(mock.Anything, &model.PatchConfigRequest{Config: model.PatchConfig{
+		DNS:  map[string]any{"upstreams": []any{"1.1.1.1"}, "interface": "eth0"},
+		DHCP: map[string]any{"active": true},
+	}}).Once().Return(nil)
+	replica.EXPECT().String().Return("http://replica")
+	dhcpServer.EXPECT().String().Return("http://dhcp")
+
+	require.NoError(t, target.syncConfigs(context.Background(), configSettings))
+}
+
+func Test_target_syncConfigs_transform(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	path := filepath.Join(t.TempDir(), "transform.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"key": "dhcp.router", "set": "{{ .Host }}"}]`), 0o600))
+	rules, err := transform.Load(path)
+	require.NoError(t, err)
+
+	target := target{
+		Primary:  primary,
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{TransformRules: rules}),
+	}
+
+	configResponse := emptyConfigResponse()
+	configResponse.Config["dhcp"] = map[string]any{"router": "10.0.0.1", "active": true}
+
+	configSettings := disabledConfigSettings()
+	configSettings.DHCP = config.NewConfigSetting(true, nil, nil)
+
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(configResponse, nil)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(emptyConfigResponse(), nil)
+	replica.EXPECT().PatchConfig(mock.Anything, &model.PatchConfigRequest{Config: model.PatchConfig{
+		DHCP: map[string]any{"router": "10.0.0.2", "active": true},
+	}}).Once().Return(nil)
+	replica.EXPECT().String().Return("http://10.0.0.2")
+
+	require.NoError(t, target.syncConfigs(context.Background(), configSettings))
+}
+
+func Test_target_syncConfigs_merge(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	rule, err := merge.NewRule("dns.hosts", merge.Union, []string{"*.lan"}, nil)
+	require.NoError(t, err)
+
+	target := target{
+		Primary:  primary,
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{MergeRules: []*merge.Rule{rule}}),
+	}
+
+	configResponse := emptyConfigResponse()
+	configResponse.Config["dns"] = map[string]any{"hosts": []any{"10.0.0.1 a.lan", "10.0.0.9 primary.home"}}
+
+	replicaConfig := emptyConfigResponse()
+	replicaConfig.Config["dns"] = map[string]any{"hosts": []any{"10.0.0.3 local.home"}}
+
+	configSettings := disabledConfigSettings()
+	configSettings.DNS = config.NewConfigSetting(true, nil, nil)
+
+	primary.EXPECT().GetConfig(mock.Anything).Once().Return(configResponse, nil)
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(replicaConfig, nil)
+	replica.EXPECT().PatchConfig(mock.Anything, &model.PatchConfigRequest{Config: model.PatchConfig{
+		DNS: map[string]any{"hosts": []any{"10.0.0.3 local.home", "10.0.0.1 a.lan"}},
+	}}).Once().Return(nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	require.NoError(t, target.syncConfigs(context.Background(), configSettings))
+}
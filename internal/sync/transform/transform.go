@@ -0,0 +1,215 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/lovelaze/nebula-sync/internal/sync/filter"
+)
+
+// Rule rewrites the value of a config key for each replica.
+// Exactly one of Set, Replace or Regex must be given, strings in Set and With are Go templates executed with Vars.
+type Rule struct {
+	Key     string `json:"key"`
+	Set     any    `json:"set,omitempty"`
+	Replace string `json:"replace,omitempty"`
+	Regex   string `json:"regex,omitempty"`
+	With    string `json:"with,omitempty"`
+
+	regex *regexp.Regexp
+	set   *template.Template
+	with  *template.Template
+}
+
+// Vars are the per replica variables available in templates.
+type Vars struct {
+	// URL is the full replica URL
+	URL string
+	// Host is the hostname or IP of the replica URL
+	Host string
+	// Name is the first label of Host, or Host itself if it is an IP address
+	Name string
+	// Index is the position of the replica in REPLICAS, starting at 1
+	Index int
+}
+
+// Load reads and compiles the rules of a JSON file.
+func Load(path string) ([]*Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read transform file: %w", err)
+	}
+
+	var rules []*Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse transform file: %w", err)
+	}
+
+	for i, rule := range rules {
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("transform rule %d (%s): %w", i+1, rule.Key, err)
+		}
+	}
+
+	return rules, nil
+}
+
+func (rule *Rule) compile() error {
+	if rule.Key == "" {
+		return errors.New("key is required")
+	}
+
+	actions := 0
+	for _, set := range []bool{rule.Set != nil, rule.Replace != "", rule.Regex != ""} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return errors.New("exactly one of set, replace or regex is required")
+	}
+
+	var err error
+	if text, ok := rule.Set.(string); ok {
+		if rule.set, err = template.New(rule.Key).Option("missingkey=error").Parse(text); err != nil {
+			return fmt.Errorf("parse set template: %w", err)
+		}
+	}
+
+	if rule.with, err = template.New(rule.Key).Option("missingkey=error").Parse(rule.With); err != nil {
+		return fmt.Errorf("parse with template: %w", err)
+	}
+
+	if rule.Regex != "" {
+		if rule.regex, err = regexp.Compile(rule.Regex); err != nil {
+			return fmt.Errorf("compile regex: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Apply returns a copy of config with every rule applied. Rules for keys missing in config are skipped.
+func Apply(rules []*Rule, config map[string]any, vars Vars) (map[string]any, error) {
+	if len(rules) == 0 {
+		return config, nil
+	}
+
+	result := deepCopy(config)
+	for _, rule := range rules {
+		keys := filter.SplitKey(rule.Key)
+		parent := lookupParent(result, keys)
+		if parent == nil {
+			continue
+		}
+
+		last := keys[len(keys)-1]
+		value, exists := parent[last]
+		if !exists {
+			continue
+		}
+
+		transformed, err := rule.apply(value, vars)
+		if err != nil {
+			return nil, fmt.Errorf("transform %s: %w", rule.Key, err)
+		}
+		parent[last] = transformed
+	}
+
+	return result, nil
+}
+
+func (rule *Rule) apply(value any, vars Vars) (any, error) {
+	if rule.Set != nil {
+		if rule.set == nil {
+			return rule.Set, nil
+		}
+		return execute(rule.set, vars)
+	}
+
+	with, err := execute(rule.with, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapStrings(value, func(s string) string {
+		if rule.regex != nil {
+			return rule.regex.ReplaceAllString(s, with)
+		}
+		return strings.ReplaceAll(s, rule.Replace, with)
+	}), nil
+}
+
+// mapStrings applies fn to value if it is a string, or to every string element if it is an array.
+func mapStrings(value any, fn func(string) string) any {
+	switch v := value.(type) {
+	case string:
+		return fn(v)
+	case []any:
+		mapped := make([]any, len(v))
+		for i, element := range v {
+			mapped[i] = mapStrings(element, fn)
+		}
+		return mapped
+	default:
+		return value
+	}
+}
+
+func execute(tmpl *template.Template, vars Vars) (string, error) {
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, vars); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+	return buffer.String(), nil
+}
+
+func lookupParent(config map[string]any, keys []string) map[string]any {
+	current := config
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+func deepCopy(original map[string]any) map[string]any {
+	copied := make(map[string]any, len(original))
+	for key, value := range original {
+		if nested, ok := value.(map[string]any); ok {
+			copied[key] = deepCopy(nested)
+		} else {
+			copied[key] = value
+		}
+	}
+	return copied
+}
+
+// NewVars returns the template variables of the replica at replicaURL, index starting at 1.
+func NewVars(replicaURL string, index int) Vars {
+	vars := Vars{URL: replicaURL, Index: index}
+
+	parsed, err := url.Parse(replicaURL)
+	if err != nil {
+		return vars
+	}
+
+	vars.Host = parsed.Hostname()
+	vars.Name = vars.Host
+	if net.ParseIP(vars.Host) == nil {
+		vars.Name, _, _ = strings.Cut(vars.Host, ".")
+	}
+
+	return vars
+}
@@ -0,0 +1,91 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadRules(t *testing.T, content string) []*Rule {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "transform.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	rules, err := Load(path)
+	require.NoError(t, err)
+	return rules
+}
+
+func TestApply(t *testing.T) {
+	rules := loadRules(t, `[
+		{"key": "dhcp.router", "set": "{{ .Host }}"},
+		{"key": "dns.interface", "set": "eth{{ .Index }}"},
+		{"key": "dns.reply.host.IPv4", "regex": "^192\\.168\\.1\\.\\d+$", "with": "{{ .Host }}"},
+		{"key": "misc.dnsmasq_lines", "replace": "192.168.1.10", "with": "{{ .Host }}"},
+		{"key": "dns.port", "set": 5353},
+		{"key": "ntp.ipv4.active", "set": false}
+	]`)
+
+	config := map[string]any{
+		"dhcp": map[string]any{"router": "192.168.1.10"},
+		"dns": map[string]any{
+			"interface": "eth0",
+			"port":      53.0,
+			"reply":     map[string]any{"host": map[string]any{"IPv4": "192.168.1.10"}},
+		},
+		"misc": map[string]any{"dnsmasq_lines": []any{"address=/nas/192.168.1.10", "local-ttl=60"}},
+	}
+
+	result, err := Apply(rules, config, NewVars("https://192.168.1.11:443", 2))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"dhcp": map[string]any{"router": "192.168.1.11"},
+		"dns": map[string]any{
+			"interface": "eth2",
+			"port":      5353.0,
+			"reply":     map[string]any{"host": map[string]any{"IPv4": "192.168.1.11"}},
+		},
+		"misc": map[string]any{"dnsmasq_lines": []any{"address=/nas/192.168.1.11", "local-ttl=60"}},
+	}, result)
+	assert.Equal(t, "192.168.1.10", config["dhcp"].(map[string]any)["router"], "input must not change")
+}
+
+func TestApply_dottedKey(t *testing.T) {
+	rules := loadRules(t, `[{"key": "dns.hosts\\.local", "set": "{{ .Host }}"}]`)
+
+	config := map[string]any{"dns": map[string]any{"hosts.local": "192.168.1.10"}}
+
+	result, err := Apply(rules, config, NewVars("http://192.168.1.11", 1))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"dns": map[string]any{"hosts.local": "192.168.1.11"}}, result)
+}
+
+func TestLoad_invalid(t *testing.T) {
+	tests := map[string]string{
+		`[{"set": "x"}]`:                                "key is required",
+		`[{"key": "dns.interface"}]`:                    "exactly one of set, replace or regex is required",
+		`[{"key": "dns.interface", "set": "{{ .Host"}]`: "parse set template",
+		`[{"key": "dns.interface", "regex": "("}]`:      "compile regex",
+	}
+
+	for content, expected := range tests {
+		path := filepath.Join(t.TempDir(), "transform.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		_, err := Load(path)
+		assert.ErrorContains(t, err, expected, content)
+	}
+}
+
+func TestNewVars(t *testing.T) {
+	assert.Equal(t, Vars{URL: "http://ph2.example.com:8080", Host: "ph2.example.com", Name: "ph2", Index: 1},
+		NewVars("http://ph2.example.com:8080", 1))
+	assert.Equal(t, Vars{URL: "http://10.0.0.2", Host: "10.0.0.2", Name: "10.0.0.2", Index: 3},
+		NewVars("http://10.0.0.2", 3))
+}
@@ -0,0 +1,67 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/sync/diff"
+)
+
+// VerificationError is returned when a replica's config does not match the primary after it was patched.
+type VerificationError struct {
+	Replica    string
+	Mismatches []diff.Change
+}
+
+func (e *VerificationError) Error() string {
+	mismatches := make([]string, 0, len(e.Mismatches))
+	for _, mismatch := range e.Mismatches {
+		mismatches = append(mismatches, fmt.Sprintf("%s: expected %v, got %v", mismatch.Key, mismatch.To, mismatch.From))
+	}
+
+	return fmt.Sprintf("replica %s did not converge: %s", e.Replica, strings.Join(mismatches, "; "))
+}
+
+// verifyConfigs re-reads the config of every replica and compares it to the config synced from the primary.
+func (target *target) verifyConfigs(ctx context.Context) error {
+	log.Info().Msg("Verifying configs...")
+	if target.run == nil {
+		return nil
+	}
+
+	return target.forEachReplica(func(replica pihole.Client) error {
+		desired := target.run.desiredConfig(replica)
+		if desired == nil {
+			return nil
+		}
+		return target.verifyConfig(ctx, replica, desired)
+	})
+}
+
+// verifyConfig reports every key of desired that the replica rejected or normalised.
+func (target *target) verifyConfig(ctx context.Context, replica pihole.Client, desired map[string]any) error {
+	mismatches, err := target.configChanges(ctx, replica, desired)
+	if err != nil {
+		return err
+	}
+
+	if len(mismatches) == 0 {
+		log.Info().Str("replica", replica.String()).Msg("Config verified")
+		return nil
+	}
+
+	for _, mismatch := range mismatches {
+		log.Warn().
+			Str("replica", replica.String()).
+			Str("key", mismatch.Key).
+			Any("expected", mismatch.To).
+			Any("actual", mismatch.From).
+			Msg("Config key not applied")
+	}
+
+	return &VerificationError{Replica: replica.String(), Mismatches: mismatches}
+}
@@ -0,0 +1,58 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/sync/diff"
+)
+
+func Test_target_verifyConfigs(t *testing.T) {
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{}),
+	}
+	target.run.setDesired(replica, map[string]any{"dns": map[string]any{"upstreams": []any{"1.1.1.1"}}})
+
+	replicaConfig := emptyConfigResponse()
+	replicaConfig.Config["dns"] = map[string]any{"upstreams": []any{"1.1.1.1"}}
+
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(replicaConfig, nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	require.NoError(t, target.verifyConfigs(context.Background()))
+}
+
+func Test_target_verifyConfigs_mismatch(t *testing.T) {
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{}),
+	}
+	target.run.setDesired(replica, map[string]any{"dns": map[string]any{"domain": map[string]any{"name": "LAN"}}})
+
+	replicaConfig := emptyConfigResponse()
+	replicaConfig.Config["dns"] = map[string]any{"domain": map[string]any{"name": "lan"}}
+
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(replicaConfig, nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	err := target.verifyConfigs(context.Background())
+
+	var verificationError *VerificationError
+	require.ErrorAs(t, err, &verificationError)
+	assert.Equal(t, []diff.Change{
+		{Key: "dns.domain.name", Path: []string{"dns", "domain", "name"}, From: "lan", To: "LAN"},
+	}, verificationError.Mismatches)
+	assert.EqualError(t, verificationError, "replica http://replica did not converge: dns.domain.name: expected LAN, got lan")
+}
@@ -0,0 +1,206 @@
+package sync
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	gosync "sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/sync/retry"
+)
+
+// Compatibility of a replica with the primary, by their FTL versions.
+const (
+	// CompatibilityFull replicas run the same or a newer version than the primary.
+	CompatibilityFull = "full"
+	// CompatibilityLimited replicas run an older version, config keys they do not have are not synced.
+	CompatibilityLimited = "limited"
+	// CompatibilityNone replicas run another major version and are not synced.
+	CompatibilityNone = "none"
+	// CompatibilityUnknown replicas run a version that cannot be compared, e.g. a development build. They are synced
+	// like fully compatible ones.
+	CompatibilityUnknown = "unknown"
+)
+
+// supportedMajor is the major Pi-hole version whose API nebula-sync speaks.
+const supportedMajor = 6
+
+var versionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// Versions are the Pi-hole versions of the primary and the replicas, detected at the start of a run.
+type Versions struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Primary   TargetVersion   `json:"primary"`
+	Replicas  []TargetVersion `json:"replicas"`
+}
+
+// TargetVersion is the version of every Pi-hole component of a target, empty if it could not be detected.
+type TargetVersion struct {
+	Target string `json:"target"`
+	Core   string `json:"core"`
+	Web    string `json:"web"`
+	FTL    string `json:"ftl"`
+	Docker string `json:"docker,omitempty"`
+	// Compatibility is the compatibility of a replica with the primary
+	Compatibility string `json:"compatibility,omitempty"`
+}
+
+// VersionCallback is notified of the versions detected by every run.
+type VersionCallback interface {
+	OnVersions(versions *Versions)
+}
+
+// VersionReporter is implemented by targets that detect the versions of their Pi-holes.
+type VersionReporter interface {
+	// Versions returns the versions detected by the latest run, nil if none detected them yet.
+	Versions() *Versions
+}
+
+// IncompatibleVersionError is returned for a target whose Pi-hole version cannot be synced.
+type IncompatibleVersionError struct {
+	Target  string
+	Version string
+	Reason  string
+}
+
+func (e *IncompatibleVersionError) Error() string {
+	return fmt.Sprintf("%s runs Pi-hole FTL %s, %s", e.Target, e.Version, e.Reason)
+}
+
+// version is a parsed release version, e.g. v6.0.4.
+type version struct {
+	major, minor, patch int
+}
+
+func parseVersion(text string) (version, bool) {
+	match := versionPattern.FindStringSubmatch(text)
+	if match == nil {
+		return version{}, false
+	}
+
+	parsed := version{}
+	parsed.major, _ = strconv.Atoi(match[1])
+	parsed.minor, _ = strconv.Atoi(match[2])
+	parsed.patch, _ = strconv.Atoi(match[3])
+	return parsed, true
+}
+
+func (v version) compare(other version) int {
+	return cmp.Or(cmp.Compare(v.major, other.major), cmp.Compare(v.minor, other.minor), cmp.Compare(v.patch, other.patch))
+}
+
+// compatibility returns the compatibility of a replica running FTL replicaFTL with a primary running primaryFTL.
+func compatibility(primaryFTL, replicaFTL string) string {
+	replica, replicaKnown := parseVersion(replicaFTL)
+	if replicaKnown && replica.major != supportedMajor {
+		return CompatibilityNone
+	}
+
+	primary, primaryKnown := parseVersion(primaryFTL)
+	switch {
+	case !primaryKnown || !replicaKnown:
+		return CompatibilityUnknown
+	case replica.compare(primary) < 0:
+		return CompatibilityLimited
+	default:
+		return CompatibilityFull
+	}
+}
+
+// Versions returns the versions detected by the latest run, nil if none detected them yet.
+func (target *target) Versions() *Versions {
+	target.versionsMu.RLock()
+	defer target.versionsMu.RUnlock()
+
+	return target.versions
+}
+
+// detectVersions reads the version of every Pi-hole and refuses to sync those whose API is not compatible.
+// Pi-holes whose version cannot be read are synced as before.
+func (target *target) detectVersions(ctx context.Context) error {
+	log.Info().Msg("Detecting versions...")
+
+	primary := target.version(ctx, target.Primary)
+	if parsed, known := parseVersion(primary.FTL); known && parsed.major != supportedMajor {
+		return &IncompatibleVersionError{
+			Target:  primary.Target,
+			Version: primary.FTL,
+			Reason:  fmt.Sprintf("only v%d is supported", supportedMajor),
+		}
+	}
+
+	versions := &Versions{Timestamp: time.Now(), Primary: *primary}
+	var mu gosync.Mutex
+
+	err := target.forEachReplica(func(replica pihole.Client) error {
+		replicaVersion := target.version(ctx, replica)
+		replicaVersion.Compatibility = compatibility(primary.FTL, replicaVersion.FTL)
+		target.run.setCompatibility(replica, replicaVersion.Compatibility)
+
+		mu.Lock()
+		versions.Replicas = append(versions.Replicas, *replicaVersion)
+		mu.Unlock()
+
+		switch replicaVersion.Compatibility {
+		case CompatibilityNone:
+			return &IncompatibleVersionError{
+				Target:  replicaVersion.Target,
+				Version: replicaVersion.FTL,
+				Reason:  "the primary runs " + primary.FTL,
+			}
+		case CompatibilityLimited:
+			log.Warn().
+				Str("replica", replicaVersion.Target).
+				Str("replica_ftl", replicaVersion.FTL).
+				Str("primary_ftl", primary.FTL).
+				Msg("Replica runs an older version than the primary, config keys it does not have are not synced")
+		}
+		return nil
+	})
+
+	sortByReplica(target.Replicas, versions.Replicas, func(version TargetVersion) string {
+		return version.Target
+	})
+
+	target.versionsMu.Lock()
+	target.versions = versions
+	target.versionsMu.Unlock()
+
+	return err
+}
+
+// version reads and logs the version of client, leaving it empty if it cannot be read.
+func (target *target) version(ctx context.Context, client pihole.Client) *TargetVersion {
+	targetVersion := &TargetVersion{Target: client.String()}
+
+	if err := retry.Fixed(ctx, func() error {
+		response, err := client.GetVersion(ctx)
+		if err != nil {
+			return err
+		}
+
+		targetVersion.Core = response.Version.Core.Local.Version
+		targetVersion.Web = response.Version.Web.Local.Version
+		targetVersion.FTL = response.Version.FTL.Local.Version
+		targetVersion.Docker = response.Version.Docker.Local
+		return nil
+	}, retry.AttemptsGetVersion); err != nil {
+		log.Warn().Err(err).Str("target", targetVersion.Target).Msg("Failed to detect version")
+		return targetVersion
+	}
+
+	log.Info().
+		Str("target", targetVersion.Target).
+		Str("core", targetVersion.Core).
+		Str("web", targetVersion.Web).
+		Str("ftl", targetVersion.FTL).
+		Str("docker", targetVersion.Docker).
+		Msg("Detected version")
+	return targetVersion
+}
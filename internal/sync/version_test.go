@@ -0,0 +1,135 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lovelaze/nebula-sync/internal/config"
+	piholemock "github.com/lovelaze/nebula-sync/internal/mocks/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole"
+	"github.com/lovelaze/nebula-sync/internal/pihole/model"
+	"github.com/lovelaze/nebula-sync/internal/sync/diff"
+)
+
+func versionResponse(ftl string) *model.VersionResponse {
+	response := &model.VersionResponse{}
+	response.Version.Core.Local.Version = "v6.0.5"
+	response.Version.Web.Local.Version = "v6.0.2"
+	response.Version.FTL.Local.Version = ftl
+	return response
+}
+
+func Test_compatibility(t *testing.T) {
+	tests := []struct {
+		primary, replica string
+		want             string
+	}{
+		{"v6.0.4", "v6.0.4", CompatibilityFull},
+		{"v6.0.4", "v6.1", CompatibilityFull},
+		{"v6.1.0", "v6.0.4", CompatibilityLimited},
+		{"v6.0.4", "v5.25.2", CompatibilityNone},
+		{"v6.0.4", "vDev-1a2b3c4", CompatibilityUnknown},
+		{"", "v6.0.4", CompatibilityUnknown},
+		{"", "v7.0.0", CompatibilityNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.primary+"/"+tt.replica, func(t *testing.T) {
+			assert.Equal(t, tt.want, compatibility(tt.primary, tt.replica))
+		})
+	}
+}
+
+func Test_target_detectVersions(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	older := piholemock.NewClient(t)
+	unknown := piholemock.NewClient(t)
+
+	target := target{
+		Primary:  primary,
+		Replicas: []pihole.Client{older, unknown},
+		run:      newRun(&config.Sync{}),
+	}
+
+	primary.EXPECT().String().Return("http://primary")
+	older.EXPECT().String().Return("http://older")
+	unknown.EXPECT().String().Return("http://unknown")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.1.0"), nil)
+	older.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	unknown.EXPECT().GetVersion(mock.Anything).Return(nil, errors.New("not found"))
+
+	require.NoError(t, target.detectVersions(context.Background()))
+
+	versions := target.Versions()
+	require.NotNil(t, versions)
+	assert.Equal(t, TargetVersion{Target: "http://primary", Core: "v6.0.5", Web: "v6.0.2", FTL: "v6.1.0"}, versions.Primary)
+	assert.Equal(t, []TargetVersion{
+		{Target: "http://older", Core: "v6.0.5", Web: "v6.0.2", FTL: "v6.0.4", Compatibility: CompatibilityLimited},
+		{Target: "http://unknown", Compatibility: CompatibilityUnknown},
+	}, versions.Replicas)
+	assert.Equal(t, CompatibilityLimited, target.run.compatibility(older))
+}
+
+func Test_target_detectVersions_incompatible(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Primary:  primary,
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{}),
+	}
+
+	primary.EXPECT().String().Return("http://primary")
+	replica.EXPECT().String().Return("http://replica")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v6.0.4"), nil)
+	replica.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v5.25.2"), nil)
+
+	err := target.detectVersions(context.Background())
+
+	var incompatibleError *IncompatibleVersionError
+	require.ErrorAs(t, err, &incompatibleError)
+	assert.EqualError(t, incompatibleError, "http://replica runs Pi-hole FTL v5.25.2, the primary runs v6.0.4")
+	assert.Equal(t, CompatibilityNone, target.Versions().Replicas[0].Compatibility)
+}
+
+func Test_target_detectVersions_unsupportedPrimary(t *testing.T) {
+	primary := piholemock.NewClient(t)
+	target := target{Primary: primary, run: newRun(&config.Sync{})}
+
+	primary.EXPECT().String().Return("http://primary")
+	primary.EXPECT().GetVersion(mock.Anything).Once().Return(versionResponse("v7.0.0"), nil)
+
+	assert.EqualError(t, target.detectVersions(context.Background()),
+		"http://primary runs Pi-hole FTL v7.0.0, only v6 is supported")
+	assert.Nil(t, target.Versions())
+}
+
+func Test_target_configChanges_limited(t *testing.T) {
+	replica := piholemock.NewClient(t)
+
+	target := target{
+		Replicas: []pihole.Client{replica},
+		run:      newRun(&config.Sync{}),
+	}
+	target.run.setCompatibility(replica, CompatibilityLimited)
+
+	replicaConfig := emptyConfigResponse()
+	replicaConfig.Config["dns"] = map[string]any{"upstreams": []any{"8.8.8.8"}}
+
+	replica.EXPECT().GetConfig(mock.Anything).Once().Return(replicaConfig, nil)
+	replica.EXPECT().String().Return("http://replica")
+
+	changes, err := target.configChanges(context.Background(), replica, map[string]any{
+		"dns": map[string]any{"upstreams": []any{"1.1.1.1"}, "blockTTL": 2.0},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []diff.Change{
+		{Key: "dns.upstreams", Path: []string{"dns", "upstreams"}, From: []any{"8.8.8.8"}, To: []any{"1.1.1.1"}},
+	}, changes)
+}
@@ -3,7 +3,10 @@ package webhook
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"strings"
 	"time"
@@ -11,6 +14,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/lovelaze/nebula-sync/internal/config"
+	"github.com/lovelaze/nebula-sync/internal/sync"
 	"github.com/lovelaze/nebula-sync/version"
 )
 
@@ -22,6 +26,8 @@ const (
 type Client struct {
 	success    config.WebhookRequest
 	failure    config.WebhookRequest
+	partial    config.WebhookRequest
+	drift      config.WebhookRequest
 	httpClient *http.Client
 }
 
@@ -29,6 +35,8 @@ func NewClient(c *config.WebhookSettings) *Client {
 	return &Client{
 		success: c.Success,
 		failure: c.Failure,
+		partial: c.Partial,
+		drift:   c.Drift,
 		httpClient: &http.Client{
 			Timeout: timeout,
 			Transport: &http.Transport{
@@ -45,11 +53,47 @@ func (c *Client) OnSuccess() {
 }
 
 func (c *Client) OnFailure(err error) {
+	var replicasError *sync.ReplicasError
+	if errors.As(err, &replicasError) && replicasError.Partial() && c.partial.URL != "" {
+		if err := c.triggerPartial(); err != nil {
+			log.Warn().Err(err).Msg("Webhook trigger failed")
+		}
+		return
+	}
+
 	if err := c.triggerFailure(); err != nil {
 		log.Warn().Err(err).Msg("Webhook trigger failed")
 	}
 }
 
+// OnDrift triggers the drift webhook if a replica drifted. Without a configured body the drift is sent as JSON.
+func (c *Client) OnDrift(drift *sync.Drift) {
+	if !drift.Detected() {
+		return
+	}
+
+	request := c.drift
+	if request.Body == "" {
+		body, err := json.Marshal(drift)
+		if err != nil {
+			log.Warn().Err(err).Msg("Webhook trigger failed")
+			return
+		}
+		request.Body = string(body)
+		request.Headers = maps.Clone(request.Headers)
+		if request.Headers == nil {
+			request.Headers = map[string]string{}
+		}
+		if _, exists := request.Headers["Content-Type"]; !exists {
+			request.Headers["Content-Type"] = "application/json"
+		}
+	}
+
+	if err := invoke(c.httpClient, request); err != nil {
+		log.Warn().Err(err).Msg("Webhook trigger failed")
+	}
+}
+
 func (c *Client) triggerSuccess() error {
 	return invoke(c.httpClient, c.success)
 }
@@ -58,6 +102,10 @@ func (c *Client) triggerFailure() error {
 	return invoke(c.httpClient, c.failure)
 }
 
+func (c *Client) triggerPartial() error {
+	return invoke(c.httpClient, c.partial)
+}
+
 func invoke(client *http.Client, settings config.WebhookRequest) error {
 	if settings.URL == "" {
 		return nil
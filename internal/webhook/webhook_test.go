@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/lovelaze/nebula-sync/internal/config"
+	"github.com/lovelaze/nebula-sync/internal/sync"
 	"github.com/lovelaze/nebula-sync/version"
 )
 
@@ -78,6 +79,33 @@ func TestWebhook(t *testing.T) {
 		assert.Equal(t, "failure", receivedHeaders.Get("X-Test"))
 	})
 
+	t.Run("partial failure uses partial configuration", func(t *testing.T) {
+		var receivedPath string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedPath = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer ts.Close()
+
+		settings := &config.WebhookSettings{
+			Failure: config.WebhookRequest{URL: ts.URL + "/failure", Method: "POST"},
+			Partial: config.WebhookRequest{URL: ts.URL + "/partial", Method: "POST"},
+		}
+
+		client := NewClient(settings)
+
+		client.OnFailure(&sync.ReplicasError{Outcomes: []sync.ReplicaOutcome{
+			{Replica: "http://replica1", Success: true},
+			{Replica: "http://replica2", Success: false},
+		}})
+		assert.Equal(t, "/partial", receivedPath)
+
+		client.OnFailure(&sync.ReplicasError{Outcomes: []sync.ReplicaOutcome{
+			{Replica: "http://replica1", Success: false},
+		}})
+		assert.Equal(t, "/failure", receivedPath)
+	})
+
 	t.Run("empty url skips webhook", func(t *testing.T) {
 		settings := &config.WebhookSettings{
 			Success: config.WebhookRequest{
@@ -108,3 +136,33 @@ func TestWebhook(t *testing.T) {
 		assert.Contains(t, err.Error(), "webhook returned status 400")
 	})
 }
+
+func TestWebhook_OnDrift(t *testing.T) {
+	var receivedHeaders http.Header
+	var receivedBody string
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		receivedHeaders = r.Header
+		buf, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		receivedBody = string(buf)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := NewClient(&config.WebhookSettings{
+		Drift: config.WebhookRequest{URL: ts.URL, Method: "POST"},
+	})
+
+	client.OnDrift(&sync.Drift{Replicas: []sync.ReplicaDrift{{Replica: "http://replica"}}})
+	assert.Equal(t, 0, calls)
+
+	client.OnDrift(&sync.Drift{Replicas: []sync.ReplicaDrift{{Replica: "http://replica", Gravity: []string{"adlist"}}}})
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "application/json", receivedHeaders.Get("Content-Type"))
+	assert.JSONEq(t,
+		`{"timestamp":"0001-01-01T00:00:00Z","replicas":[{"replica":"http://replica","gravity":["adlist"]}]}`,
+		receivedBody,
+	)
+}